@@ -4,10 +4,14 @@ import (
 	"context"
 	"github.com/engineerXIII/maiSystemBackend/config"
 	server "github.com/engineerXIII/maiSystemBackend/internal/service/inventory"
+	"github.com/engineerXIII/maiSystemBackend/pkg/amqp/rabbitmq"
+	"github.com/engineerXIII/maiSystemBackend/pkg/db/postgres"
 	"github.com/engineerXIII/maiSystemBackend/pkg/db/redis"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
 	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	redis2 "github.com/go-redis/redis/v8"
+	"github.com/golang-migrate/migrate/v4"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/opentracing/opentracing-go"
 	"github.com/uber/jaeger-client-go"
@@ -39,6 +43,32 @@ func main() {
 	appLogger.InitLogger()
 	appLogger.Infof("AppVersion: %s, LogLevel: %s, Mode: %s, SSL: %v", cfg.Server.AppVersion, cfg.Logger.Level, cfg.Server.Mode, cfg.Server.SSL)
 
+	psqlDB, err := postgres.NewPsqlDB(cfg)
+	if err != nil {
+		appLogger.Fatalf("Postgresql init: %s", err)
+	} else {
+		appLogger.Infof("Postgres connected, Status: %#v", psqlDB.Stats())
+	}
+
+	defer psqlDB.Close()
+
+	driver, err := migratePostgres.WithInstance(psqlDB.DB, &migratePostgres.Config{})
+	if err != nil {
+		appLogger.Fatalf("Cannot create migration driver: %s", err)
+	}
+
+	migration, err := migrate.NewWithDatabaseInstance(
+		"file://db/migrations",
+		"postgres", driver)
+	if err != nil {
+		appLogger.Fatalf("Error on initiate migration: %s", err)
+	}
+	status := migration.Up()
+	if status != nil {
+		appLogger.Infof("Migration status: %s", status)
+	}
+	appLogger.Info("Migration completed")
+
 	redisClient := redis.NewRedisClient(cfg)
 	ctx, _ := context.WithTimeout(context.Background(), time.Second)
 	err = redisClient.Set(ctx, "conn", 1, 1000).Err()
@@ -48,6 +78,28 @@ func main() {
 	defer redisClient.Close()
 	appLogger.Info("Redis connected")
 
+	amqpClient, err := rabbitmq.NewAMQP(cfg)
+	if err != nil {
+		appLogger.Fatalf("Cannot connect to AMQP: %s", err)
+	}
+	defer func() {
+		_ = amqpClient.Close()
+	}()
+	appLogger.Info("AMQP connected")
+
+	amqpChannel, err := rabbitmq.CreateChannel(amqpClient)
+	if err != nil {
+		appLogger.Fatalf("Cannot open channel AMQP: %s", err)
+	}
+	defer func() {
+		_ = amqpChannel.Close()
+	}()
+	appLogger.Info("AMQP channel opened")
+	amqpQueue, err := rabbitmq.DeclareQueue(amqpChannel, cfg)
+	if err != nil {
+		appLogger.Fatalf("Cannot open channel AMQP: %s", err)
+	}
+
 	jaegerCfgInstance := jaegercfg.Configuration{
 		ServiceName: cfg.Jaeger.ServiceName,
 		Sampler: &jaegercfg.SamplerConfig{
@@ -73,7 +125,7 @@ func main() {
 	defer closer.Close()
 	appLogger.Info("Opentracing connected")
 
-	s := server.NewServer(cfg, redisClient, appLogger)
+	s := server.NewServer(cfg, psqlDB, amqpChannel, amqpQueue, redisClient, appLogger)
 	if err = s.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	inventoryClient "github.com/engineerXIII/maiSystemBackend/internal/inventory/client"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory/csvfile"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+const inventoryCSVTimeout = 10 * time.Minute
+
+// Bulk stock import and export through inventory service
+//
+//	go run ./cmd/api/inventory_csv.go [-reason receipt] [-actor name] import stock.csv
+//	go run ./cmd/api/inventory_csv.go export stock.csv
+func main() {
+	reason := flag.String("reason", string(models.InventoryMovementReasonReceipt), "ledger reason of imported stock")
+	actor := flag.String("actor", "inventory-csv", "ledger actor of imported stock")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] import|export file.csv\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	configPath := utils.GetConfigPath(os.Getenv("CONFIG_TYPE"))
+
+	cfgFile, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("LoadConfig: %v", err)
+	}
+
+	cfg, err := config.ParseConfig(cfgFile)
+	if err != nil {
+		log.Fatalf("ParseConfig: %v", err)
+	}
+
+	inventoryConn, err := inventoryClient.NewInventoryConn(cfg)
+	if err != nil {
+		log.Fatalf("GRPC not connect: %v", err)
+	}
+	defer inventoryConn.Close()
+	client := inventoryClient.NewInventoryClient(pb.NewInventoryServiceClient(inventoryConn))
+
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryCSVTimeout)
+	defer cancel()
+
+	switch command, path := flag.Arg(0), flag.Arg(1); command {
+	case "import":
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Open: %v", err)
+		}
+		defer file.Close()
+
+		rows, rowErrors, err := csvfile.ReadRows(file)
+		if err != nil {
+			log.Fatalf("ReadRows: %v", err)
+		}
+		change := models.StockChange{Reason: models.InventoryMovementReason(*reason), Actor: *actor}
+		result, err := client.ImportItems(ctx, rows, change)
+		if err != nil {
+			log.Fatalf("ImportItems: %v", err)
+		}
+
+		rowErrors = append(rowErrors, result.Errors...)
+		sort.SliceStable(rowErrors, func(i, j int) bool {
+			return rowErrors[i].Line < rowErrors[j].Line
+		})
+		for _, rowError := range rowErrors {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, rowError.Line, rowError.Message)
+		}
+		fmt.Printf("Imported %d rows, rejected %d rows\n", result.Imported, len(rowErrors))
+		if len(rowErrors) > 0 {
+			os.Exit(1)
+		}
+	case "export":
+		items, err := client.ExportItems(ctx)
+		if err != nil {
+			log.Fatalf("ExportItems: %v", err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("Create: %v", err)
+		}
+		if err = csvfile.WriteItems(file, items); err != nil {
+			log.Fatalf("WriteItems: %v", err)
+		}
+		if err = file.Close(); err != nil {
+			log.Fatalf("Close: %v", err)
+		}
+		fmt.Printf("Exported %d rows\n", len(items))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"github.com/engineerXIII/maiSystemBackend/config"
 	server "github.com/engineerXIII/maiSystemBackend/internal/service/order"
 	"github.com/engineerXIII/maiSystemBackend/pkg/amqp/rabbitmq"
+	"github.com/engineerXIII/maiSystemBackend/pkg/db/postgres"
 	"github.com/engineerXIII/maiSystemBackend/pkg/db/redis"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
 	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	"github.com/go-co-op/gocron"
+	"github.com/golang-migrate/migrate/v4"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/opentracing/opentracing-go"
 	"github.com/uber/jaeger-client-go"
@@ -39,6 +42,32 @@ func main() {
 	appLogger.InitLogger()
 	appLogger.Infof("AppVersion: %s, LogLevel: %s, Mode: %s, SSL: %v", cfg.Server.AppVersion, cfg.Logger.Level, cfg.Server.Mode, cfg.Server.SSL)
 
+	psqlDB, err := postgres.NewPsqlDB(cfg)
+	if err != nil {
+		appLogger.Fatalf("Postgresql init: %s", err)
+	} else {
+		appLogger.Infof("Postgres connected, Status: %#v", psqlDB.Stats())
+	}
+
+	defer psqlDB.Close()
+
+	driver, err := migratePostgres.WithInstance(psqlDB.DB, &migratePostgres.Config{})
+	if err != nil {
+		appLogger.Fatalf("Cannot create migration driver: %s", err)
+	}
+
+	migration, err := migrate.NewWithDatabaseInstance(
+		"file://db/migrations",
+		"postgres", driver)
+	if err != nil {
+		appLogger.Fatalf("Error on initiate migration: %s", err)
+	}
+	status := migration.Up()
+	if status != nil {
+		appLogger.Infof("Migration status: %s", status)
+	}
+	appLogger.Info("Migration completed")
+
 	redisClient := redis.NewRedisClient(cfg)
 	defer redisClient.Close()
 	appLogger.Info("Redis connected")
@@ -93,7 +122,7 @@ func main() {
 	cron := gocron.NewScheduler(time.UTC)
 	appLogger.Info("Cron started")
 
-	s := server.NewServer(cfg, amqpChannel, amqpQueue, redisClient, cron, appLogger)
+	s := server.NewServer(cfg, psqlDB, amqpChannel, amqpQueue, redisClient, cron, appLogger)
 	if err = s.Run(); err != nil {
 		log.Fatal(err)
 	}
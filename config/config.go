@@ -11,17 +11,21 @@ import (
 
 // App config struct
 type Config struct {
-	Server   ServerConfig
-	Service  Service
-	Docs     Docs
-	Postgres PostgresConfig
-	RabbitMQ RabbitMQConfig
-	Redis    RedisConfig
-	Cookie   Cookie
-	Session  Session
-	Metrics  Metrics
-	Jaeger   Jaeger
-	Logger   Logger
+	Server     ServerConfig
+	Service    Service
+	GRPC       GRPC
+	Allocation Allocation
+	Processing Processing
+	LowStock   LowStock
+	Docs       Docs
+	Postgres   PostgresConfig
+	RabbitMQ   RabbitMQConfig
+	Redis      RedisConfig
+	Cookie     Cookie
+	Session    Session
+	Metrics    Metrics
+	Jaeger     Jaeger
+	Logger     Logger
 }
 
 // Jaeger configuration
@@ -56,6 +60,41 @@ type ServerConfig struct {
 
 type Service struct {
 	Inventory string
+	Product   string
+}
+
+// gRPC transport security between services, insecure mode disables TLS for local development.
+// Reflection exposes the API to tools like grpcurl and is meant for debugging
+type GRPC struct {
+	Insecure          bool
+	CAFile            string
+	CertFile          string
+	KeyFile           string
+	ServerName        string
+	RequireClientCert bool
+	Reflection        bool
+}
+
+// Warehouse allocation used when order is packaged, location is the delivery point of nearest strategy
+type Allocation struct {
+	Strategy  string
+	Latitude  float64
+	Longitude float64
+}
+
+// Order processing pipeline, workers consume durable queue of order stages.
+// Failed stages are retried after retry delay in seconds.
+// Order is locked for lock TTL in seconds while one replica processes it, lock is renewed until stage is done
+type Processing struct {
+	Queue      string
+	Workers    int
+	RetryDelay int
+	LockTTL    int
+}
+
+// Low stock alerts, default threshold applies to items without own threshold
+type LowStock struct {
+	DefaultThreshold int
 }
 
 // Logger config
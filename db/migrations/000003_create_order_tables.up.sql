@@ -0,0 +1,26 @@
+DROP TABLE IF EXISTS order_items CASCADE;
+DROP TABLE IF EXISTS orders CASCADE;
+
+CREATE TABLE orders
+(
+    order_id       UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    status         INTEGER                  NOT NULL DEFAULT 1,
+    status_message VARCHAR(32)              NOT NULL DEFAULT '',
+    sum            INTEGER                  NOT NULL DEFAULT 0 CHECK ( sum >= 0 ),
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX orders_status_idx ON orders (status);
+
+CREATE TABLE order_items
+(
+    order_item_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id      UUID    NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    item_id       UUID    NOT NULL,
+    cost          INTEGER NOT NULL CHECK ( cost > 0 ),
+    qty           INTEGER NOT NULL CHECK ( qty > 0 ),
+    sum           INTEGER NOT NULL CHECK ( sum >= 0 )
+);
+
+CREATE INDEX order_items_order_id_idx ON order_items (order_id);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS order_status_history CASCADE;
+
+CREATE TABLE order_status_history
+(
+    history_id  UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    order_id    UUID                     NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    from_status INTEGER                  NOT NULL,
+    to_status   INTEGER                  NOT NULL,
+    actor       VARCHAR(16)              NOT NULL CHECK ( actor <> '' ),
+    actor_id    UUID,
+    reason      VARCHAR(256)             NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX order_status_history_order_id_idx ON order_status_history (order_id, created_at);
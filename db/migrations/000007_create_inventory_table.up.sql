@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS inventory_items CASCADE;
+
+CREATE TABLE inventory_items
+(
+    item_id    UUID PRIMARY KEY,
+    qty        INTEGER                  NOT NULL DEFAULT 0 CHECK ( qty >= 0 ),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
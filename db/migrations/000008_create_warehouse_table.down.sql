@@ -0,0 +1,8 @@
+DELETE FROM inventory_items
+WHERE warehouse_id <> '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE inventory_items DROP CONSTRAINT inventory_items_pkey;
+ALTER TABLE inventory_items DROP COLUMN IF EXISTS warehouse_id;
+ALTER TABLE inventory_items ADD PRIMARY KEY (item_id);
+
+DROP TABLE IF EXISTS warehouses CASCADE;
@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS warehouses CASCADE;
+
+CREATE TABLE warehouses
+(
+    warehouse_id UUID PRIMARY KEY,
+    name         VARCHAR(250)             NOT NULL,
+    address      VARCHAR(500)             NOT NULL DEFAULT '',
+    latitude     DOUBLE PRECISION         NOT NULL DEFAULT 0,
+    longitude    DOUBLE PRECISION         NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Stock stored before warehouses were introduced is kept in the default warehouse
+INSERT INTO warehouses (warehouse_id, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+ALTER TABLE inventory_items
+    ADD COLUMN warehouse_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+        REFERENCES warehouses (warehouse_id) ON DELETE CASCADE;
+ALTER TABLE inventory_items ALTER COLUMN warehouse_id DROP DEFAULT;
+ALTER TABLE inventory_items DROP CONSTRAINT inventory_items_pkey;
+ALTER TABLE inventory_items ADD PRIMARY KEY (item_id, warehouse_id);
@@ -0,0 +1,36 @@
+DROP TABLE IF EXISTS inventory_movements CASCADE;
+
+CREATE TABLE inventory_movements
+(
+    movement_id  UUID PRIMARY KEY                  DEFAULT uuid_generate_v4(),
+    item_id      UUID                     NOT NULL,
+    warehouse_id UUID                     NOT NULL,
+    delta        INTEGER                  NOT NULL CHECK ( delta <> 0 ),
+    reason       VARCHAR(16)              NOT NULL CHECK ( reason IN ('receipt', 'order', 'adjustment', 'return') ),
+    order_id     UUID,
+    actor        VARCHAR(250)             NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX inventory_movements_item_id_idx ON inventory_movements (item_id, warehouse_id, created_at);
+CREATE INDEX inventory_movements_order_id_idx ON inventory_movements (order_id);
+
+-- Ledger is append-only
+CREATE OR REPLACE FUNCTION inventory_movements_immutable() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'inventory_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER inventory_movements_immutable
+    BEFORE UPDATE OR DELETE
+    ON inventory_movements
+    FOR EACH ROW
+EXECUTE PROCEDURE inventory_movements_immutable();
+
+-- Opening balance of stock stored before the ledger
+INSERT INTO inventory_movements (item_id, warehouse_id, delta, reason, actor)
+SELECT item_id, warehouse_id, qty, 'adjustment', 'migration'
+FROM inventory_items
+WHERE qty > 0;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS inventory_thresholds CASCADE;
+
+CREATE TABLE inventory_thresholds
+(
+    item_id    UUID PRIMARY KEY,
+    threshold  INTEGER                  NOT NULL CHECK ( threshold >= 0 ),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE          DEFAULT CURRENT_TIMESTAMP
+);
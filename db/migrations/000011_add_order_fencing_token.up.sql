@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fencing_token BIGINT NOT NULL DEFAULT 0;
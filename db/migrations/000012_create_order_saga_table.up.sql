@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS order_sagas CASCADE;
+
+CREATE TABLE order_sagas
+(
+    order_id       UUID PRIMARY KEY         NOT NULL REFERENCES orders (order_id) ON DELETE CASCADE,
+    step           INTEGER                  NOT NULL DEFAULT 0,
+    state          INTEGER                  NOT NULL,
+    reservation_id UUID,
+    payment_id     VARCHAR(128),
+    shipment_id    VARCHAR(128),
+    failure        VARCHAR(256)             NOT NULL DEFAULT '',
+    fencing_token  BIGINT                   NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS inventory_reservation_items CASCADE;
+DROP TABLE IF EXISTS inventory_reservations CASCADE;
+
+CREATE TABLE inventory_reservations
+(
+    reservation_id UUID PRIMARY KEY,
+    order_id       UUID                     NOT NULL,
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX inventory_reservations_expires_at_idx ON inventory_reservations (expires_at);
+
+CREATE TABLE inventory_reservation_items
+(
+    reservation_id UUID    NOT NULL REFERENCES inventory_reservations (reservation_id) ON DELETE CASCADE,
+    item_id        UUID    NOT NULL,
+    qty            INTEGER NOT NULL CHECK ( qty > 0 ),
+    PRIMARY KEY (reservation_id, item_id)
+);
+
+CREATE INDEX inventory_reservation_items_item_id_idx ON inventory_reservation_items (item_id);
@@ -0,0 +1,3 @@
+ALTER TABLE order_sagas DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE order_sagas DROP COLUMN IF EXISTS cancel_actor_id;
+ALTER TABLE order_sagas DROP COLUMN IF EXISTS cancel_actor;
@@ -0,0 +1,3 @@
+ALTER TABLE order_sagas ADD COLUMN IF NOT EXISTS cancel_actor VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE order_sagas ADD COLUMN IF NOT EXISTS cancel_actor_id UUID;
+ALTER TABLE order_sagas ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR(256) NOT NULL DEFAULT '';
@@ -0,0 +1,16 @@
+//go:generate mockgen -source client.go -destination mock/client_mock.go -package mock
+package inventory
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Inventory service client for other services
+type Client interface {
+	Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error)
+	ReleaseReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID) error
+	ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error)
+	ExportItems(ctx context.Context) ([]*models.InventoryItem, error)
+}
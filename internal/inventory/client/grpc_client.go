@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	grpcInterceptors "github.com/engineerXIII/maiSystemBackend/pkg/grpc"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
+)
+
+var pbMovementReasons = map[models.InventoryMovementReason]pb.MovementReason{
+	models.InventoryMovementReasonReceipt:    pb.MovementReason_ReasonReceipt,
+	models.InventoryMovementReasonOrder:      pb.MovementReason_ReasonOrder,
+	models.InventoryMovementReasonAdjustment: pb.MovementReason_ReasonAdjustment,
+	models.InventoryMovementReasonReturn:     pb.MovementReason_ReasonReturn,
+}
+
+// Inventory service gRPC client
+type inventoryClient struct {
+	grpcClient pb.InventoryServiceClient
+}
+
+// Inventory client constructor
+func NewInventoryClient(grpcClient pb.InventoryServiceClient) inventory.Client {
+	return &inventoryClient{grpcClient: grpcClient}
+}
+
+// Dial inventory service with transport credentials of gRPC config, extra options are appended to the dial
+func NewInventoryConn(cfg *config.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	creds, err := grpcInterceptors.NewClientCredentials(cfg.GRPC)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewInventoryConn.NewClientCredentials")
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, opts...)
+	conn, err := grpc.Dial(cfg.Service.Inventory, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewInventoryConn.Dial")
+	}
+	return conn, nil
+}
+
+// Reserve order items, returns short items with available quantity
+func (c *inventoryClient) Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryClient.Reserve")
+	defer span.Finish()
+
+	req := &pb.ReserveRequest{
+		OrderId: reservation.OrderID.String(),
+		Item:    make([]*pb.Item, 0, len(reservation.Items)),
+	}
+	if reservation.ReservationID != uuid.Nil {
+		req.ReservationId = reservation.ReservationID.String()
+	}
+	if !reservation.ExpiresAt.IsZero() {
+		req.TtlSeconds = uint64(time.Until(reservation.ExpiresAt).Seconds())
+	}
+	for _, item := range reservation.Items {
+		req.Item = append(req.Item, &pb.Item{
+			Uuid: item.UUID.String(),
+			Qty:  uint64(item.Qty),
+		})
+	}
+
+	resp, err := c.grpcClient.Reserve(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(reservationStatusError(err), "inventoryClient.Reserve")
+	}
+
+	switch resp.Status {
+	case pb.Status_OK:
+		reservationID, err := uuid.Parse(resp.ReservationId)
+		if err != nil {
+			return nil, errors.Wrap(err, "inventoryClient.Reserve.uuid.Parse")
+		}
+		reservation.ReservationID = reservationID
+		reservation.ExpiresAt = time.Unix(resp.ExpiresAt, 0)
+		return nil, nil
+	case pb.Status_NotEnoughAvailable:
+		shortItems := make([]*models.InventoryItem, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			itemID, err := uuid.Parse(item.Item.Uuid)
+			if err != nil {
+				return nil, errors.Wrap(err, "inventoryClient.Reserve.uuid.Parse")
+			}
+			shortItems = append(shortItems, &models.InventoryItem{UUID: itemID, Qty: int(item.Item.Qty)})
+		}
+		return shortItems, nil
+	default:
+		return nil, errors.Errorf("inventoryClient.Reserve: %s %s", resp.Status, resp.StatusMessage)
+	}
+}
+
+// Return reserved items back to stock
+func (c *inventoryClient) ReleaseReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryClient.ReleaseReservation")
+	defer span.Finish()
+
+	resp, err := c.grpcClient.ReleaseReservation(ctx, &pb.ReservationRequest{
+		ReservationId: reservationID.String(),
+		OrderId:       orderID.String(),
+	})
+	if err != nil {
+		return errors.Wrap(reservationStatusError(err), "inventoryClient.ReleaseReservation")
+	}
+	return reservationResponseError(resp)
+}
+
+// Stream import rows to inventory service, returns rows rejected by the service
+func (c *inventoryClient) ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryClient.ImportItems")
+	defer span.Finish()
+
+	stream, err := c.grpcClient.ImportItems(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryClient.ImportItems")
+	}
+	for _, row := range rows {
+		req := &pb.ImportRow{
+			Line:   uint32(row.Line),
+			Item:   &pb.Item{Uuid: row.Item.UUID.String(), Qty: uint64(row.Item.Qty)},
+			Reason: pbMovementReasons[change.Reason],
+			Actor:  change.Actor,
+		}
+		if row.Item.WarehouseID != uuid.Nil {
+			req.Item.WarehouseId = row.Item.WarehouseID.String()
+		}
+		if err = stream.Send(req); err != nil {
+			return nil, errors.Wrap(err, "inventoryClient.ImportItems.Send")
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryClient.ImportItems.CloseAndRecv")
+	}
+	if resp.Status != pb.Status_OK {
+		return nil, errors.Errorf("inventoryClient.ImportItems: %s %s", resp.Status, resp.StatusMessage)
+	}
+
+	result := &models.InventoryImportResult{Imported: int(resp.Imported), Errors: make([]*models.InventoryImportError, 0, len(resp.Errors))}
+	for _, rowError := range resp.Errors {
+		result.Errors = append(result.Errors, &models.InventoryImportError{Line: int(rowError.Line), Message: rowError.Message})
+	}
+	return result, nil
+}
+
+// Get stocks of all items per warehouse
+func (c *inventoryClient) ExportItems(ctx context.Context) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryClient.ExportItems")
+	defer span.Finish()
+
+	stream, err := c.grpcClient.ExportItems(ctx, &pb.ExportRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryClient.ExportItems")
+	}
+
+	stocks := make([]*models.InventoryItem, 0)
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			return stocks, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "inventoryClient.ExportItems.Recv")
+		}
+		itemID, err := uuid.Parse(item.Uuid)
+		if err != nil {
+			return nil, errors.Wrap(err, "inventoryClient.ExportItems.uuid.Parse")
+		}
+		warehouseID, err := uuid.Parse(item.WarehouseId)
+		if err != nil {
+			return nil, errors.Wrap(err, "inventoryClient.ExportItems.uuid.Parse")
+		}
+		stocks = append(stocks, &models.InventoryItem{UUID: itemID, WarehouseID: warehouseID, Qty: int(item.Qty)})
+	}
+}
+
+// Map reservation response status to error
+func reservationResponseError(resp *pb.Response) error {
+	switch resp.Status {
+	case pb.Status_OK:
+		return nil
+	case pb.Status_NotFound:
+		return errors.Wrap(httpErrors.NotFound, resp.StatusMessage)
+	default:
+		return errors.Errorf("%s %s", resp.Status, resp.StatusMessage)
+	}
+}
+
+// Map reservation gRPC status error, missing reservation becomes not found error
+func reservationStatusError(err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+		return errors.Wrap(httpErrors.NotFound, st.Message())
+	}
+	return err
+}
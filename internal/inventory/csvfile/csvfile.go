@@ -0,0 +1,89 @@
+package csvfile
+
+import (
+	"encoding/csv"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Columns of stock file, warehouse may be empty for default warehouse
+var header = []string{"item_id", "warehouse_id", "qty"}
+
+// Read stock rows, malformed rows are reported by line and skipped
+func ReadRows(r io.Reader) ([]*models.InventoryImportRow, []*models.InventoryImportError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows := make([]*models.InventoryImportRow, 0)
+	rowErrors := make([]*models.InventoryImportError, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, rowErrors, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, &models.InventoryImportError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "csvfile.ReadRows.Read")
+		}
+
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(record[0], header[0]) {
+			continue
+		}
+		item, err := parseRecord(record)
+		if err != nil {
+			rowErrors = append(rowErrors, &models.InventoryImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		rows = append(rows, &models.InventoryImportRow{Line: line, Item: item})
+	}
+}
+
+// Write stocks with header row
+func WriteItems(w io.Writer, items []*models.InventoryItem) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return errors.Wrap(err, "csvfile.WriteItems.Write")
+	}
+	for _, item := range items {
+		warehouseID := ""
+		if item.WarehouseID != uuid.Nil {
+			warehouseID = item.WarehouseID.String()
+		}
+		if err := writer.Write([]string{item.UUID.String(), warehouseID, strconv.Itoa(item.Qty)}); err != nil {
+			return errors.Wrap(err, "csvfile.WriteItems.Write")
+		}
+	}
+	writer.Flush()
+	return errors.Wrap(writer.Error(), "csvfile.WriteItems.Flush")
+}
+
+func parseRecord(record []string) (*models.InventoryItem, error) {
+	if len(record) != len(header) {
+		return nil, errors.Errorf("expected %d fields, got %d", len(header), len(record))
+	}
+	itemID, err := uuid.Parse(record[0])
+	if err != nil {
+		return nil, errors.Errorf("invalid item id %q", record[0])
+	}
+	warehouseID := uuid.Nil
+	if record[1] != "" {
+		if warehouseID, err = uuid.Parse(record[1]); err != nil {
+			return nil, errors.Errorf("invalid warehouse id %q", record[1])
+		}
+	}
+	qty, err := strconv.Atoi(record[2])
+	if err != nil || qty <= 0 {
+		return nil, errors.Errorf("invalid quantity %q", record[2])
+	}
+	return &models.InventoryItem{UUID: itemID, WarehouseID: warehouseID, Qty: qty}, nil
+}
@@ -0,0 +1,56 @@
+package csvfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+func TestReadRows(t *testing.T) {
+	t.Parallel()
+
+	itemID, warehouseID := uuid.New(), uuid.New()
+	input := strings.Join([]string{
+		"item_id,warehouse_id,qty",
+		itemID.String() + "," + warehouseID.String() + ",5",
+		"not-a-uuid,,3",
+		itemID.String() + ",,0",
+		itemID.String() + ",,7,extra",
+		itemID.String() + ",,2",
+	}, "\n")
+
+	rows, rowErrors, err := ReadRows(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []*models.InventoryImportRow{
+		{Line: 2, Item: &models.InventoryItem{UUID: itemID, WarehouseID: warehouseID, Qty: 5}},
+		{Line: 6, Item: &models.InventoryItem{UUID: itemID, Qty: 2}},
+	}, rows)
+	require.Len(t, rowErrors, 3)
+	require.Equal(t, 3, rowErrors[0].Line)
+	require.Equal(t, 4, rowErrors[1].Line)
+	require.Equal(t, 5, rowErrors[2].Line)
+}
+
+func TestWriteItems(t *testing.T) {
+	t.Parallel()
+
+	items := []*models.InventoryItem{
+		{UUID: uuid.New(), WarehouseID: models.DefaultWarehouseID, Qty: 4},
+		{UUID: uuid.New(), WarehouseID: uuid.New(), Qty: 1},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteItems(&buf, items))
+
+	rows, rowErrors, err := ReadRows(&buf)
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.Len(t, rows, 2)
+	require.Equal(t, items[0], rows[0].Item)
+	require.Equal(t, items[1], rows[1].Item)
+}
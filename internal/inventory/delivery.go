@@ -0,0 +1,9 @@
+package inventory
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	GetByID() echo.HandlerFunc
+	GetItems() echo.HandlerFunc
+	AdjustStock() echo.HandlerFunc
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"net/http"
+)
+
+const (
+	// Error details domain of inventory service
+	errorDomain = "inventory.maisystem"
+
+	preconditionStock = "STOCK"
+	preconditionState = "STATE"
+)
+
+// Request field violations, reported together as invalid argument
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field string, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// Invalid argument error listing every violation, nil when request is valid
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("%s: %s", v[0].Field, v[0].Description)
+	if len(v) > 1 {
+		message = fmt.Sprintf("%s and %d more violations", message, len(v)-1)
+	}
+	return statusError(codes.InvalidArgument, message, &errdetails.BadRequest{FieldViolations: v})
+}
+
+// Build status error with details, falls back to bare status when details can not be attached
+func statusError(code codes.Code, message string, details ...protoiface.MessageV1) error {
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// Map use case errors to gRPC statuses, unexpected errors are logged and hidden behind internal status
+func (s InventoryServer) useCaseError(err error) error {
+	var restErr httpErrors.RestErr
+	switch {
+	case errors.Is(err, httpErrors.NotFound):
+		return statusError(codes.NotFound, err.Error(), &errdetails.ErrorInfo{Reason: "NOT_FOUND", Domain: errorDomain})
+	case errors.Is(err, httpErrors.NotEnoughStock):
+		return statusError(codes.FailedPrecondition, err.Error(), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{Type: preconditionStock, Description: err.Error()}},
+		})
+	case errors.Is(err, httpErrors.Conflict):
+		return statusError(codes.FailedPrecondition, err.Error(), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{Type: preconditionState, Description: err.Error()}},
+		})
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &restErr):
+		return restStatusError(restErr)
+	default:
+		s.logger.Errorf("inventory gRPC: %s", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// Map rest errors of use case, short items of conflict become stock violations
+func restStatusError(restErr httpErrors.RestErr) error {
+	message := fmt.Sprint(restErr.Causes())
+	switch restErr.Status() {
+	case http.StatusBadRequest:
+		return statusError(codes.InvalidArgument, message, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Description: message}},
+		})
+	case http.StatusNotFound:
+		return statusError(codes.NotFound, message, &errdetails.ErrorInfo{Reason: "NOT_FOUND", Domain: errorDomain})
+	case http.StatusConflict:
+		shortItems, ok := restErr.Causes().([]*models.InventoryItem)
+		if !ok {
+			return statusError(codes.FailedPrecondition, message, &errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{Type: preconditionState, Description: message}},
+			})
+		}
+		if e, ok := restErr.(httpErrors.RestError); ok {
+			message = e.ErrError
+		}
+		return statusError(codes.FailedPrecondition, message, stockViolations(shortItems))
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// Precondition violation per short item with its available quantity
+func stockViolations(shortItems []*models.InventoryItem) *errdetails.PreconditionFailure {
+	failure := &errdetails.PreconditionFailure{Violations: make([]*errdetails.PreconditionFailure_Violation, 0, len(shortItems))}
+	for _, item := range shortItems {
+		failure.Violations = append(failure.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        preconditionStock,
+			Subject:     item.UUID.String(),
+			Description: fmt.Sprintf("available %d", item.Qty),
+		})
+	}
+	return failure
+}
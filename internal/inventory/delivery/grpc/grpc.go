@@ -2,15 +2,37 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"github.com/engineerXIII/maiSystemBackend/config"
 	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
 )
 
+const (
+	// Movements page size when request does not set it
+	defaultMovementsSize = 50
+	// Largest item quantity stock columns hold
+	maxItemQty = math.MaxInt32
+)
+
+var movementReasons = map[pb.MovementReason]models.InventoryMovementReason{
+	pb.MovementReason_ReasonReceipt:    models.InventoryMovementReasonReceipt,
+	pb.MovementReason_ReasonOrder:      models.InventoryMovementReasonOrder,
+	pb.MovementReason_ReasonAdjustment: models.InventoryMovementReasonAdjustment,
+	pb.MovementReason_ReasonReturn:     models.InventoryMovementReasonReturn,
+}
+
 type InventoryServer struct {
 	cfg         *config.Config
 	inventoryUC inventory.UseCase
@@ -26,93 +48,764 @@ func NewInventoryServer(cfg *config.Config, inventoryUC inventory.UseCase, logge
 	}
 }
 
+// Check available quantity of items in aggregate, or in the warehouse when it is set
 func (s InventoryServer) CheckItem(c context.Context, in *pb.ItemRequest) (*pb.ItemAvailableResponse, error) {
 	span, ctx := opentracing.StartSpanFromContext(c, "inventory.CheckItem")
 	defer span.Finish()
 
+	var v violations
+	items := parseItems(in.Item, false, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
 	response := &pb.ItemAvailableResponse{}
 	status := pb.Status_OK
-	for _, item := range in.Item {
-		uuidItem, _ := uuid.Parse(item.Uuid)
-		foundItem, err := s.inventoryUC.GetItemByID(ctx, uuidItem)
+	for i, item := range in.Item {
+		foundItem, err := s.inventoryUC.GetAvailableItem(ctx, items[i].UUID)
 		if err != nil {
-			return nil, err
+			return nil, s.useCaseError(err)
 		} else if foundItem == nil {
 			response.Items = append(response.Items, &pb.ItemAvailableStatus{
 				Item: &pb.Item{
-					Uuid: item.Uuid,
-					Qty:  0,
+					Uuid:        item.Uuid,
+					Qty:         0,
+					WarehouseId: item.WarehouseId,
 				},
 				Status: pb.Status_NotFound,
 			})
 			status = pb.Status_NotEnoughAvailable
-		} else {
-			qty := item.Qty
-			sts := pb.Status_OK
-			if uint64(foundItem.Qty) < item.Qty {
-				qty = uint64(foundItem.Qty)
-				sts = pb.Status_NotEnoughAvailable
-				status = pb.Status_NotEnoughAvailable
-			}
-			response.Items = append(response.Items, &pb.ItemAvailableStatus{
-				Item: &pb.Item{
-					Uuid: foundItem.UUID.String(),
-					Qty:  qty,
-				},
-				Status: sts,
-			})
+			continue
+		}
+
+		stocks, err := s.inventoryUC.GetItemStocks(ctx, items[i].UUID)
+		if err != nil {
+			return nil, s.useCaseError(err)
+		}
+		available := foundItem.Qty
+		if item.WarehouseId != "" {
+			available = warehouseAvailableQty(available, stocks, item.WarehouseId)
+		}
+
+		qty := item.Qty
+		sts := pb.Status_OK
+		if uint64(available) < item.Qty {
+			qty = uint64(available)
+			sts = pb.Status_NotEnoughAvailable
+			status = pb.Status_NotEnoughAvailable
 		}
+		response.Items = append(response.Items, &pb.ItemAvailableStatus{
+			Item: &pb.Item{
+				Uuid:        foundItem.UUID.String(),
+				Qty:         qty,
+				WarehouseId: item.WarehouseId,
+			},
+			Status:     sts,
+			Warehouses: toPbItems(stocks),
+		})
 	}
 	response.Status = status
 	return response, nil
 }
 
 func (s InventoryServer) AddItem(c context.Context, in *pb.ItemRequest) (*pb.Response, error) {
-	span, ctx := opentracing.StartSpanFromContext(c, "inventory.CheckItem")
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.AddItem")
+	defer span.Finish()
+
+	var v violations
+	items := parseItems(in.Item, true, &v)
+	change := parseStockChange(in, models.InventoryMovementReasonReceipt, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	resultItems, err := s.inventoryUC.AddItems(ctx, items, change)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+
+	return &pb.Response{
+		Status:        pb.Status_OK,
+		StatusMessage: "Created",
+		Items:         itemStatuses(resultItems, pb.Status_OK),
+	}, nil
+}
+
+func (s InventoryServer) RemoveItem(c context.Context, in *pb.ItemRequest) (*pb.Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.RemoveItem")
 	defer span.Finish()
 
-	response := &pb.Response{}
-	uid, _ := uuid.Parse(in.Item[0].Uuid)
-	item := &models.InventoryItem{
-		UUID: uid,
-		Qty:  int(in.Item[0].Qty),
+	var v violations
+	items := parseItems(in.Item, true, &v)
+	defaultReason := models.InventoryMovementReasonAdjustment
+	if in.OrderId != "" {
+		defaultReason = models.InventoryMovementReasonOrder
 	}
-	_, err := s.inventoryUC.AddItem(ctx, item)
+	change := parseStockChange(in, defaultReason, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	resultItems, shortItems, err := s.inventoryUC.RemoveItems(ctx, items, change)
 	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	if len(shortItems) > 0 {
+		short := make([]string, 0, len(shortItems))
+		for _, item := range shortItems {
+			short = append(short, item.UUID.String())
+		}
+		return &pb.Response{
+			Status:        pb.Status_NotEnoughAvailable,
+			StatusMessage: "Not enough available: " + strings.Join(short, ", "),
+			Items:         itemStatuses(shortItems, pb.Status_NotEnoughAvailable),
+		}, nil
+	}
+
+	return &pb.Response{
+		Status:        pb.Status_OK,
+		StatusMessage: "Successfull",
+		Items:         itemStatuses(resultItems, pb.Status_OK),
+	}, nil
+}
+
+func (s InventoryServer) Reserve(c context.Context, in *pb.ReserveRequest) (*pb.ReserveResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.Reserve")
+	defer span.Finish()
+
+	var v violations
+	reservation := &models.InventoryReservation{}
+	if reservationID := parseOptionalID("reservation_id", in.ReservationId, &v); reservationID != nil {
+		reservation.ReservationID = *reservationID
+	}
+	reservation.OrderID = parseID("order_id", in.OrderId, &v)
+	if in.TtlSeconds > 0 {
+		reservation.ExpiresAt = time.Now().Add(time.Duration(in.TtlSeconds) * time.Second)
+	}
+	for _, item := range parseItems(in.Item, true, &v) {
+		reservation.Items = append(reservation.Items, &models.InventoryItem{UUID: item.UUID, Qty: item.Qty})
+	}
+	if err := v.err(); err != nil {
 		return nil, err
 	}
-	response.StatusMessage = "Created"
+
+	shortItems, err := s.inventoryUC.Reserve(ctx, reservation)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+
+	response := &pb.ReserveResponse{}
+	if len(shortItems) > 0 {
+		response.Status = pb.Status_NotEnoughAvailable
+		response.StatusMessage = "Not enough stock"
+		response.Items = itemStatuses(shortItems, pb.Status_NotEnoughAvailable)
+		return response, nil
+	}
+
 	response.Status = pb.Status_OK
+	response.StatusMessage = "Reserved"
+	response.ReservationId = reservation.ReservationID.String()
+	response.ExpiresAt = reservation.ExpiresAt.Unix()
 	return response, nil
 }
 
-func (s InventoryServer) RemoveItem(c context.Context, in *pb.ItemRequest) (*pb.Response, error) {
-	span, ctx := opentracing.StartSpanFromContext(c, "inventory.CheckItem")
+func (s InventoryServer) CommitReservation(c context.Context, in *pb.ReservationRequest) (*pb.Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.CommitReservation")
 	defer span.Finish()
 
-	response := &pb.Response{}
-	status := pb.Status_OK
-	response.Status = status
-	response.StatusMessage = "Successfull"
-	for _, item := range in.Item {
-		uuidItem, _ := uuid.Parse(item.Uuid)
-		foundItem, err := s.inventoryUC.GetItemByID(ctx, uuidItem)
+	var v violations
+	reservationID, orderID := parseReservationRequest(in, &v)
+	options := parseAllocationOptions(in.Strategy, in.Location, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.inventoryUC.CommitReservation(ctx, reservationID, orderID, in.Actor, options); err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.Response{Status: pb.Status_OK, StatusMessage: "Committed"}, nil
+}
+
+func (s InventoryServer) ReleaseReservation(c context.Context, in *pb.ReservationRequest) (*pb.Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.ReleaseReservation")
+	defer span.Finish()
+
+	var v violations
+	reservationID, orderID := parseReservationRequest(in, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.inventoryUC.ReleaseReservation(ctx, reservationID, orderID); err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.Response{Status: pb.Status_OK, StatusMessage: "Released"}, nil
+}
+
+// Hold reservation longer while its order is processed
+func (s InventoryServer) ExtendReservation(c context.Context, in *pb.ExtendReservationRequest) (*pb.ReserveResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.ExtendReservation")
+	defer span.Finish()
+
+	var v violations
+	reservationID, orderID := parseID("reservation_id", in.ReservationId, &v), parseID("order_id", in.OrderId, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.inventoryUC.ExtendReservation(ctx, reservationID, orderID, time.Duration(in.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.ReserveResponse{
+		Status:        pb.Status_OK,
+		StatusMessage: "Extended",
+		ReservationId: reservation.ReservationID.String(),
+		ExpiresAt:     reservation.ExpiresAt.Unix(),
+	}, nil
+}
+
+// Plan warehouses giving items by strategy
+func (s InventoryServer) Allocate(c context.Context, in *pb.AllocateRequest) (*pb.AllocateResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.Allocate")
+	defer span.Finish()
+
+	var v violations
+	items := parseItems(in.Item, true, &v)
+	options := parseAllocationOptions(in.Strategy, in.Location, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	allocations, shortItems, err := s.inventoryUC.AllocateItems(ctx, items, options)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	if len(shortItems) > 0 {
+		return &pb.AllocateResponse{
+			Status:        pb.Status_NotEnoughAvailable,
+			StatusMessage: "Not enough stock",
+			Items:         itemStatuses(shortItems, pb.Status_NotEnoughAvailable),
+		}, nil
+	}
+
+	return &pb.AllocateResponse{
+		Status:        pb.Status_OK,
+		StatusMessage: "Allocated",
+		Allocations:   toPbItems(allocations),
+	}, nil
+}
+
+func (s InventoryServer) CreateWarehouse(c context.Context, in *pb.Warehouse) (*pb.WarehouseResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.CreateWarehouse")
+	defer span.Finish()
+
+	var v violations
+	warehouse := parseWarehouse(in, false, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	createdWarehouse, err := s.inventoryUC.CreateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.WarehouseResponse{Status: pb.Status_OK, StatusMessage: "Created", Warehouse: toPbWarehouse(createdWarehouse)}, nil
+}
+
+func (s InventoryServer) UpdateWarehouse(c context.Context, in *pb.Warehouse) (*pb.WarehouseResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.UpdateWarehouse")
+	defer span.Finish()
+
+	var v violations
+	warehouse := parseWarehouse(in, true, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	updatedWarehouse, err := s.inventoryUC.UpdateWarehouse(ctx, warehouse)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.WarehouseResponse{Status: pb.Status_OK, StatusMessage: "Updated", Warehouse: toPbWarehouse(updatedWarehouse)}, nil
+}
+
+func (s InventoryServer) GetWarehouse(c context.Context, in *pb.WarehouseRequest) (*pb.WarehouseResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.GetWarehouse")
+	defer span.Finish()
+
+	var v violations
+	warehouseID := parseID("warehouse_id", in.WarehouseId, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	warehouse, err := s.inventoryUC.GetWarehouseByID(ctx, warehouseID)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.WarehouseResponse{Status: pb.Status_OK, StatusMessage: "Found", Warehouse: toPbWarehouse(warehouse)}, nil
+}
+
+func (s InventoryServer) ListWarehouses(c context.Context, in *pb.WarehouseListRequest) (*pb.WarehouseListResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.ListWarehouses")
+	defer span.Finish()
+
+	warehouses, err := s.inventoryUC.GetWarehouses(ctx)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+
+	response := &pb.WarehouseListResponse{Status: pb.Status_OK, StatusMessage: "Found"}
+	for _, warehouse := range warehouses {
+		response.Warehouses = append(response.Warehouses, toPbWarehouse(warehouse))
+	}
+	return response, nil
+}
+
+func (s InventoryServer) DeleteWarehouse(c context.Context, in *pb.WarehouseRequest) (*pb.Response, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.DeleteWarehouse")
+	defer span.Finish()
+
+	var v violations
+	warehouseID := parseID("warehouse_id", in.WarehouseId, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.inventoryUC.DeleteWarehouse(ctx, warehouseID); err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return &pb.Response{Status: pb.Status_OK, StatusMessage: "Deleted"}, nil
+}
+
+func (s InventoryServer) GetThreshold(c context.Context, in *pb.ThresholdRequest) (*pb.ThresholdResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.GetThreshold")
+	defer span.Finish()
+
+	var v violations
+	itemID := parseID("item_id", in.ItemId, &v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	threshold, err := s.inventoryUC.GetThreshold(ctx, itemID)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return toPbThreshold(threshold, "Found"), nil
+}
+
+func (s InventoryServer) SetThreshold(c context.Context, in *pb.ThresholdRequest) (*pb.ThresholdResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.SetThreshold")
+	defer span.Finish()
+
+	var v violations
+	itemID := parseID("item_id", in.ItemId, &v)
+	if in.Threshold < 0 {
+		v.add("threshold", "threshold must not be negative")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	threshold, err := s.inventoryUC.SetThreshold(ctx, &models.ItemThreshold{ItemID: itemID, Threshold: int(in.Threshold)})
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+	return toPbThreshold(threshold, "Updated"), nil
+}
+
+// List stock ledger entries, newest first
+func (s InventoryServer) ListMovements(c context.Context, in *pb.MovementListRequest) (*pb.MovementListResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.ListMovements")
+	defer span.Finish()
+
+	var v violations
+	filter := &models.InventoryMovementFilter{
+		ItemID:      parseOptionalID("item_id", in.ItemId, &v),
+		WarehouseID: parseOptionalID("warehouse_id", in.WarehouseId, &v),
+		OrderID:     parseOptionalID("order_id", in.OrderId, &v),
+		Reason:      movementReasons[in.Reason],
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	pq := &utils.PaginationQuery{Page: int(in.Page), Size: int(in.Size)}
+	if pq.Size == 0 {
+		pq.Size = defaultMovementsSize
+	}
+
+	movementList, err := s.inventoryUC.ListMovements(ctx, filter, pq)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+
+	response := &pb.MovementListResponse{
+		Status:        pb.Status_OK,
+		StatusMessage: "Found",
+		TotalCount:    uint64(movementList.TotalCount),
+		TotalPages:    uint32(movementList.TotalPages),
+		Page:          uint32(movementList.Page),
+		Size:          uint32(movementList.Size),
+		HasMore:       movementList.HasMore,
+	}
+	for _, movement := range movementList.Movements {
+		response.Movements = append(response.Movements, toPbMovement(movement))
+	}
+	return response, nil
+}
+
+// Compare stored stock with stock rebuilt from ledger, overwrite drifted stock when apply is set
+func (s InventoryServer) ReconcileStock(c context.Context, in *pb.ReconcileRequest) (*pb.ReconcileResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(c, "inventory.ReconcileStock")
+	defer span.Finish()
+
+	drifts, err := s.inventoryUC.ReconcileStock(ctx, in.Apply)
+	if err != nil {
+		return nil, s.useCaseError(err)
+	}
+
+	response := &pb.ReconcileResponse{Status: pb.Status_OK, StatusMessage: "No drift"}
+	if len(drifts) > 0 {
+		response.StatusMessage = "Drift found"
+		if in.Apply {
+			response.StatusMessage = "Drift fixed"
+		}
+	}
+	for _, drift := range drifts {
+		response.Drifts = append(response.Drifts, &pb.StockDrift{
+			ItemId:      drift.ItemID.String(),
+			WarehouseId: drift.WarehouseID.String(),
+			StockQty:    int64(drift.StockQty),
+			LedgerQty:   int64(drift.LedgerQty),
+		})
+	}
+	return response, nil
+}
+
+// Stream available quantity of items, current quantities first and then every change until client leaves
+func (s InventoryServer) WatchItems(in *pb.WatchRequest, stream pb.InventoryService_WatchItemsServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "inventory.WatchItems")
+	defer span.Finish()
+
+	var v violations
+	itemIDs := make([]uuid.UUID, 0, len(in.Uuid))
+	for i, id := range in.Uuid {
+		itemIDs = append(itemIDs, parseID(fmt.Sprintf("uuid[%d]", i), id, &v))
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	// Subscribe before reading current quantities so no change is lost in between
+	changes, err := s.inventoryUC.WatchItems(ctx, itemIDs)
+	if err != nil {
+		return s.useCaseError(err)
+	}
+
+	for _, itemID := range itemIDs {
+		item, err := s.inventoryUC.GetAvailableItem(ctx, itemID)
+		if err != nil {
+			return s.useCaseError(err)
+		}
+		if item == nil {
+			item = &models.InventoryItem{UUID: itemID}
+		}
+		if err = stream.Send(availableStatus(item)); err != nil {
+			return err
+		}
+	}
+
+	for item := range changes {
+		if err = stream.Send(availableStatus(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import stock rows streamed by client, invalid rows are reported by line instead of failing the import
+func (s InventoryServer) ImportItems(stream pb.InventoryService_ImportItemsServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "inventory.ImportItems")
+	defer span.Finish()
+
+	var change models.StockChange
+	rows := make([]*models.InventoryImportRow, 0)
+	rowErrors := make([]*models.InventoryImportError, 0)
+	for first := true; ; first = false {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return nil, err
-		} else if foundItem != nil {
-			foundItem.Qty = foundItem.Qty - 1
-			status = pb.Status_OK
-
-			_, err := s.inventoryUC.RemoveItem(ctx, foundItem)
-			if err != nil {
-				s.logger.Error(err)
-				status = pb.Status_Error
-				response.StatusMessage = "Error during removing item"
+			return err
+		}
+		if first {
+			change = models.StockChange{Reason: movementReasons[in.Reason], Actor: in.Actor}
+			if change.Reason == "" {
+				change.Reason = models.InventoryMovementReasonReceipt
 			}
-		} else {
-			status = pb.Status_NotFound
-			response.StatusMessage = "Not found: " + item.Uuid
 		}
+
+		item, err := parseImportItem(in.Item)
+		if err != nil {
+			rowErrors = append(rowErrors, &models.InventoryImportError{Line: int(in.Line), Message: err.Error()})
+			continue
+		}
+		rows = append(rows, &models.InventoryImportRow{Line: int(in.Line), Item: item})
 	}
-	return response, nil
+
+	result, err := s.inventoryUC.ImportItems(ctx, rows, change)
+	if err != nil {
+		return s.useCaseError(err)
+	}
+
+	rowErrors = append(rowErrors, result.Errors...)
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Line < rowErrors[j].Line
+	})
+	response := &pb.ImportResponse{
+		Status:        pb.Status_OK,
+		StatusMessage: fmt.Sprintf("Imported %d rows, rejected %d rows", result.Imported, len(rowErrors)),
+		Imported:      uint64(result.Imported),
+		Errors:        make([]*pb.ImportError, 0, len(rowErrors)),
+	}
+	for _, rowError := range rowErrors {
+		response.Errors = append(response.Errors, &pb.ImportError{Line: uint32(rowError.Line), Message: rowError.Message})
+	}
+	return stream.SendAndClose(response)
+}
+
+// Stream stocks of all items per warehouse
+func (s InventoryServer) ExportItems(in *pb.ExportRequest, stream pb.InventoryService_ExportItemsServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "inventory.ExportItems")
+	defer span.Finish()
+
+	stocks, err := s.inventoryUC.ExportItems(ctx)
+	if err != nil {
+		return s.useCaseError(err)
+	}
+	for _, stock := range stocks {
+		if err = stream.Send(toPbItem(stock)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse imported item, empty warehouse is left for default warehouse
+func parseImportItem(item *pb.Item) (*models.InventoryItem, error) {
+	if item == nil {
+		return nil, errors.New("empty item")
+	}
+	itemID, err := uuid.Parse(item.Uuid)
+	if err != nil {
+		return nil, errors.Errorf("invalid item id %q", item.Uuid)
+	}
+	warehouseID := uuid.Nil
+	if item.WarehouseId != "" {
+		if warehouseID, err = uuid.Parse(item.WarehouseId); err != nil {
+			return nil, errors.Errorf("invalid warehouse id %q", item.WarehouseId)
+		}
+	}
+	if item.Qty > maxItemQty {
+		return nil, errors.Errorf("quantity must not exceed %d", maxItemQty)
+	}
+	return &models.InventoryItem{UUID: itemID, WarehouseID: warehouseID, Qty: int(item.Qty)}, nil
+}
+
+// Parse request items, collects violations of invalid item fields
+func parseItems(pbItems []*pb.Item, requireQty bool, v *violations) []*models.InventoryItem {
+	if len(pbItems) == 0 {
+		v.add("item", "at least one item is required")
+	}
+	items := make([]*models.InventoryItem, 0, len(pbItems))
+	for i, item := range pbItems {
+		field := fmt.Sprintf("item[%d]", i)
+		if item == nil {
+			v.add(field, "item is required")
+			items = append(items, &models.InventoryItem{})
+			continue
+		}
+		if requireQty && item.Qty == 0 {
+			v.add(field+".qty", "quantity must be positive")
+		}
+		if item.Qty > maxItemQty {
+			v.add(field+".qty", fmt.Sprintf("quantity must not exceed %d", maxItemQty))
+		}
+		inventoryItem := &models.InventoryItem{
+			UUID: parseID(field+".uuid", item.Uuid, v),
+			Qty:  int(item.Qty),
+		}
+		if warehouseID := parseOptionalID(field+".warehouse_id", item.WarehouseId, v); warehouseID != nil {
+			inventoryItem.WarehouseID = *warehouseID
+		}
+		items = append(items, inventoryItem)
+	}
+	return items
+}
+
+// Parse ledger details of stock change, reason falls back to default when unset
+func parseStockChange(in *pb.ItemRequest, defaultReason models.InventoryMovementReason, v *violations) models.StockChange {
+	change := models.StockChange{Reason: movementReasons[in.Reason], Actor: in.Actor}
+	if change.Reason == "" {
+		change.Reason = defaultReason
+	}
+	change.OrderID = parseOptionalID("order_id", in.OrderId, v)
+	return change
+}
+
+// Parse required id, collects violation when it is invalid
+func parseID(field string, value string, v *violations) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		v.add(field, fmt.Sprintf("invalid id %q", value))
+		return uuid.Nil
+	}
+	return id
+}
+
+// Parse id which may be empty
+func parseOptionalID(field string, value string, v *violations) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	id := parseID(field, value, v)
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+func toPbMovement(movement *models.InventoryMovement) *pb.Movement {
+	pbMovement := &pb.Movement{
+		MovementId:  movement.MovementID.String(),
+		ItemId:      movement.ItemID.String(),
+		WarehouseId: movement.WarehouseID.String(),
+		Delta:       int64(movement.Delta),
+		Actor:       movement.Actor,
+		CreatedAt:   movement.CreatedAt.Unix(),
+	}
+	for reason, name := range movementReasons {
+		if name == movement.Reason {
+			pbMovement.Reason = reason
+		}
+	}
+	if movement.OrderID != nil {
+		pbMovement.OrderId = movement.OrderID.String()
+	}
+	return pbMovement
+}
+
+// Build response items with quantities
+func itemStatuses(items []*models.InventoryItem, status pb.Status) []*pb.ItemAvailableStatus {
+	statuses := make([]*pb.ItemAvailableStatus, 0, len(items))
+	for _, item := range items {
+		statuses = append(statuses, &pb.ItemAvailableStatus{
+			Item:   toPbItem(item),
+			Status: status,
+		})
+	}
+	return statuses
+}
+
+// Build watch update of item available quantity
+func availableStatus(item *models.InventoryItem) *pb.ItemAvailableStatus {
+	sts := pb.Status_OK
+	if item.Qty <= 0 {
+		sts = pb.Status_NotEnoughAvailable
+	}
+	return &pb.ItemAvailableStatus{Item: toPbItem(item), Status: sts}
+}
+
+func toPbItems(items []*models.InventoryItem) []*pb.Item {
+	pbItems := make([]*pb.Item, 0, len(items))
+	for _, item := range items {
+		pbItems = append(pbItems, toPbItem(item))
+	}
+	return pbItems
+}
+
+func toPbItem(item *models.InventoryItem) *pb.Item {
+	pbItem := &pb.Item{
+		Uuid: item.UUID.String(),
+		Qty:  uint64(item.Qty),
+	}
+	if item.WarehouseID != uuid.Nil {
+		pbItem.WarehouseId = item.WarehouseID.String()
+	}
+	return pbItem
+}
+
+// Item quantity available in the warehouse, limited by aggregate available quantity
+func warehouseAvailableQty(available int, stocks []*models.InventoryItem, warehouseID string) int {
+	for _, stock := range stocks {
+		if stock.WarehouseID.String() == warehouseID {
+			if stock.Qty < available {
+				return stock.Qty
+			}
+			return available
+		}
+	}
+	return 0
+}
+
+// Parse allocation strategy with optional location
+func parseAllocationOptions(strategy pb.AllocationStrategy, location *pb.Location, v *violations) models.AllocationOptions {
+	options := models.AllocationOptions{}
+	switch strategy {
+	case pb.AllocationStrategy_MostStock:
+		options.Strategy = models.AllocationStrategyMostStock
+	case pb.AllocationStrategy_Nearest:
+		options.Strategy = models.AllocationStrategyNearest
+		if location == nil {
+			v.add("location", "location is required by nearest allocation")
+		}
+	default:
+		v.add("strategy", fmt.Sprintf("unknown allocation strategy %d", strategy))
+	}
+	if location != nil {
+		options.Location = &models.Location{Latitude: location.Latitude, Longitude: location.Longitude}
+	}
+	return options
+}
+
+// Parse warehouse message, id is required on update
+func parseWarehouse(in *pb.Warehouse, requireID bool, v *violations) *models.Warehouse {
+	warehouse := &models.Warehouse{
+		Name:      in.Name,
+		Address:   in.Address,
+		Latitude:  in.Latitude,
+		Longitude: in.Longitude,
+	}
+	if in.WarehouseId != "" || requireID {
+		warehouse.WarehouseID = parseID("warehouse_id", in.WarehouseId, v)
+	}
+	return warehouse
+}
+
+func toPbWarehouse(warehouse *models.Warehouse) *pb.Warehouse {
+	return &pb.Warehouse{
+		WarehouseId: warehouse.WarehouseID.String(),
+		Name:        warehouse.Name,
+		Address:     warehouse.Address,
+		Latitude:    warehouse.Latitude,
+		Longitude:   warehouse.Longitude,
+	}
+}
+
+func toPbThreshold(threshold *models.ItemThreshold, message string) *pb.ThresholdResponse {
+	return &pb.ThresholdResponse{
+		Status:        pb.Status_OK,
+		StatusMessage: message,
+		ItemId:        threshold.ItemID.String(),
+		Threshold:     int64(threshold.Threshold),
+	}
+}
+
+// Parse reservation request ids, collects violations of invalid ids
+func parseReservationRequest(in *pb.ReservationRequest, v *violations) (uuid.UUID, uuid.UUID) {
+	return parseID("reservation_id", in.ReservationId, v), parseID("order_id", in.OrderId, v)
 }
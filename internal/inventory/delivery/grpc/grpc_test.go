@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+)
+
+func newTestInventoryServer(t *testing.T) (*InventoryServer, *mock.MockUseCase) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockInventoryUC := mock.NewMockUseCase(ctrl)
+
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development: true,
+		},
+	}
+
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	return NewInventoryServer(cfg, mockInventoryUC, apiLogger), mockInventoryUC
+}
+
+func TestInventoryServer_AddItem_InvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestInventoryServer(t)
+
+	resp, err := server.AddItem(context.Background(), &pb.ItemRequest{
+		Item: []*pb.Item{
+			{Uuid: uuid.New().String(), Qty: 1},
+			{Uuid: "bad-id", Qty: 0},
+		},
+	})
+	require.Nil(t, resp)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	fields := make([]string, 0, len(badRequest.FieldViolations))
+	for _, violation := range badRequest.FieldViolations {
+		fields = append(fields, violation.Field)
+	}
+	require.ElementsMatch(t, []string{"item[1].uuid", "item[1].qty"}, fields)
+}
+
+func TestInventoryServer_RemoveItem_HugeQty(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestInventoryServer(t)
+
+	// Quantity wrapping to negative int would add stock instead of removing it
+	resp, err := server.RemoveItem(context.Background(), &pb.ItemRequest{
+		Item: []*pb.Item{{Uuid: uuid.New().String(), Qty: math.MaxUint64 - 4}},
+	})
+	require.Nil(t, resp)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 1)
+	require.Equal(t, "item[0].qty", badRequest.FieldViolations[0].Field)
+
+	_, err = parseImportItem(&pb.Item{Uuid: uuid.New().String(), Qty: math.MaxInt32 + 1})
+	require.Error(t, err)
+	item, err := parseImportItem(&pb.Item{Uuid: uuid.New().String(), Qty: math.MaxInt32})
+	require.NoError(t, err)
+	require.Equal(t, math.MaxInt32, item.Qty)
+}
+
+func TestInventoryServer_CommitReservation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotEnoughStock", func(t *testing.T) {
+		t.Parallel()
+
+		server, mockInventoryUC := newTestInventoryServer(t)
+
+		reservationID := uuid.New()
+		orderID := uuid.New()
+		shortItems := []*models.InventoryItem{{UUID: uuid.New(), Qty: 1}}
+		mockInventoryUC.EXPECT().CommitReservation(gomock.Any(), reservationID, orderID, "", gomock.Any()).
+			Return(httpErrors.NewRestErrorWithMessage(http.StatusConflict, httpErrors.NotEnoughStock.Error(), shortItems))
+
+		resp, err := server.CommitReservation(context.Background(), &pb.ReservationRequest{
+			ReservationId: reservationID.String(),
+			OrderId:       orderID.String(),
+		})
+		require.Nil(t, resp)
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		require.Equal(t, codes.FailedPrecondition, st.Code())
+		require.Len(t, st.Details(), 1)
+
+		failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+		require.True(t, ok)
+		require.Len(t, failure.Violations, 1)
+		require.Equal(t, preconditionStock, failure.Violations[0].Type)
+		require.Equal(t, shortItems[0].UUID.String(), failure.Violations[0].Subject)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		server, mockInventoryUC := newTestInventoryServer(t)
+
+		reservationID := uuid.New()
+		orderID := uuid.New()
+		mockInventoryUC.EXPECT().CommitReservation(gomock.Any(), reservationID, orderID, "", gomock.Any()).
+			Return(httpErrors.NotFound)
+
+		_, err := server.CommitReservation(context.Background(), &pb.ReservationRequest{
+			ReservationId: reservationID.String(),
+			OrderId:       orderID.String(),
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+type inventoryHandlers struct {
+	cfg         *config.Config
+	inventoryUC inventory.UseCase
+	logger      logger.Logger
+}
+
+func NewInventoryHandlers(cfg *config.Config, inventoryUC inventory.UseCase, logger logger.Logger) inventory.Handlers {
+	return &inventoryHandlers{cfg: cfg, inventoryUC: inventoryUC, logger: logger}
+}
+
+// GetByID godoc
+// @Summary Get item stock
+// @Description Get item stock with available quantity and stock per warehouse, admin only
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "item_id"
+// @Success 200 {object} models.InventoryItemDetails
+// @Router /inventory/{id} [get]
+func (h inventoryHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "inventoryHandlers.GetByID")
+		defer span.Finish()
+
+		itemUUID, err := uuid.Parse(c.Param("item_id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		details, err := h.inventoryUC.GetItemDetails(ctx, itemUUID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, details)
+	}
+}
+
+// GetItems godoc
+// @Summary Get item stock list
+// @Description Get item stocks summed over warehouses, admin only
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param page query int false "page number" Format(page)
+// @Param size query int false "size of page" Format(size)
+// @Success 200 {object} models.InventoryItemList
+// @Router /inventory [get]
+func (h inventoryHandlers) GetItems() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "inventoryHandlers.GetItems")
+		defer span.Finish()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		itemList, err := h.inventoryUC.GetItems(ctx, pq)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, itemList)
+	}
+}
+
+// AdjustStock godoc
+// @Summary Adjust item stock
+// @Description Add or take out item stock in warehouse, admin only
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "item_id"
+// @Success 200 {object} models.InventoryItemDetails
+// @Router /inventory/{id}/adjust [post]
+func (h inventoryHandlers) AdjustStock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "inventoryHandlers.AdjustStock")
+		defer span.Finish()
+
+		itemUUID, err := uuid.Parse(c.Param("item_id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		adjustment := &models.StockAdjustment{}
+		if err = c.Bind(adjustment); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		user, err := utils.GetUserFromCtx(ctx)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		details, err := h.inventoryUC.AdjustStock(ctx, itemUUID, adjustment, user.Email)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, details)
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/converter"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+)
+
+func TestInventoryHandlers_AdjustStock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInventoryUC := mock.NewMockUseCase(ctrl)
+
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development: true,
+		},
+	}
+
+	apiLogger := logger.NewApiLogger(cfg)
+	inventoryHandlers := NewInventoryHandlers(cfg, mockInventoryUC, apiLogger)
+
+	itemID := uuid.New()
+	adjustment := &models.StockAdjustment{WarehouseID: models.DefaultWarehouseID, Delta: -2}
+	buf, err := converter.AnyToBytesBuffer(adjustment)
+	require.NoError(t, err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/"+itemID.String()+"/adjust", strings.NewReader(buf.String()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	admin := &models.User{UserID: uuid.New(), Email: "admin@mail.com"}
+	req = req.WithContext(context.WithValue(req.Context(), utils.UserCtxKey{}, admin))
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetParamNames("item_id")
+	c.SetParamValues(itemID.String())
+
+	details := &models.InventoryItemDetails{ItemID: itemID, Qty: 3, Available: 3}
+	mockInventoryUC.EXPECT().AdjustStock(gomock.Any(), itemID, gomock.Eq(adjustment), admin.Email).Return(details, nil)
+
+	err = inventoryHandlers.AdjustStock()(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	response := &models.InventoryItemDetails{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), response))
+	require.Equal(t, details, response)
+}
+
+func TestInventoryHandlers_GetByID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockInventoryUC := mock.NewMockUseCase(ctrl)
+
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development: true,
+		},
+	}
+
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	inventoryHandlers := NewInventoryHandlers(cfg, mockInventoryUC, apiLogger)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inventory/not-a-uuid", nil)
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetParamNames("item_id")
+	c.SetParamValues("not-a-uuid")
+
+	err := inventoryHandlers.GetByID()(c)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
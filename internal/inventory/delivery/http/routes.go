@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func MapInventoryRoutes(inventoryGroup *echo.Group, h inventory.Handlers, mw *middleware.MiddlewareManager) {
+	inventoryGroup.Use(mw.AuthSessionMiddleware, mw.AdminMiddleware)
+	inventoryGroup.GET("/:item_id", h.GetByID())
+	inventoryGroup.POST("/:item_id/adjust", h.AdjustStock(), mw.CSRF)
+	inventoryGroup.GET("", h.GetItems())
+}
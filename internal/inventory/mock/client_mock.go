@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: client.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// ExportItems mocks base method.
+func (m *MockClient) ExportItems(ctx context.Context) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportItems", ctx)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportItems indicates an expected call of ExportItems.
+func (mr *MockClientMockRecorder) ExportItems(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportItems", reflect.TypeOf((*MockClient)(nil).ExportItems), ctx)
+}
+
+// ImportItems mocks base method.
+func (m *MockClient) ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportItems", ctx, rows, change)
+	ret0, _ := ret[0].(*models.InventoryImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportItems indicates an expected call of ImportItems.
+func (mr *MockClientMockRecorder) ImportItems(ctx, rows, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportItems", reflect.TypeOf((*MockClient)(nil).ImportItems), ctx, rows, change)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockClient) ReleaseReservation(ctx context.Context, reservationID, orderID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, reservationID, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockClientMockRecorder) ReleaseReservation(ctx, reservationID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockClient)(nil).ReleaseReservation), ctx, reservationID, orderID)
+}
+
+// Reserve mocks base method.
+func (m *MockClient) Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, reservation)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockClientMockRecorder) Reserve(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockClient)(nil).Reserve), ctx, reservation)
+}
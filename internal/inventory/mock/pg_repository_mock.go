@@ -0,0 +1,366 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pg_repository.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	utils "github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddItems mocks base method.
+func (m *MockRepository) AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItems", ctx, items, change)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddItems indicates an expected call of AddItems.
+func (mr *MockRepositoryMockRecorder) AddItems(ctx, items, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItems", reflect.TypeOf((*MockRepository)(nil).AddItems), ctx, items, change)
+}
+
+// CommitReservation mocks base method.
+func (m *MockRepository) CommitReservation(ctx context.Context, reservationID uuid.UUID, stocks []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitReservation", ctx, reservationID, stocks, change)
+	ret0, _ := ret[0].(*models.StockRemoval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitReservation indicates an expected call of CommitReservation.
+func (mr *MockRepositoryMockRecorder) CommitReservation(ctx, reservationID, stocks, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitReservation", reflect.TypeOf((*MockRepository)(nil).CommitReservation), ctx, reservationID, stocks, change)
+}
+
+// CreateReservation mocks base method.
+func (m *MockRepository) CreateReservation(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReservation", ctx, reservation)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReservation indicates an expected call of CreateReservation.
+func (mr *MockRepositoryMockRecorder) CreateReservation(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReservation", reflect.TypeOf((*MockRepository)(nil).CreateReservation), ctx, reservation)
+}
+
+// CreateWarehouse mocks base method.
+func (m *MockRepository) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWarehouse", ctx, warehouse)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWarehouse indicates an expected call of CreateWarehouse.
+func (mr *MockRepositoryMockRecorder) CreateWarehouse(ctx, warehouse interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouse", reflect.TypeOf((*MockRepository)(nil).CreateWarehouse), ctx, warehouse)
+}
+
+// DeleteReservation mocks base method.
+func (m *MockRepository) DeleteReservation(ctx context.Context, reservationID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReservation", ctx, reservationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReservation indicates an expected call of DeleteReservation.
+func (mr *MockRepositoryMockRecorder) DeleteReservation(ctx, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReservation", reflect.TypeOf((*MockRepository)(nil).DeleteReservation), ctx, reservationID)
+}
+
+// DeleteWarehouse mocks base method.
+func (m *MockRepository) DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWarehouse", ctx, warehouseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWarehouse indicates an expected call of DeleteWarehouse.
+func (mr *MockRepositoryMockRecorder) DeleteWarehouse(ctx, warehouseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWarehouse", reflect.TypeOf((*MockRepository)(nil).DeleteWarehouse), ctx, warehouseID)
+}
+
+// ExtendReservation mocks base method.
+func (m *MockRepository) ExtendReservation(ctx context.Context, reservationID uuid.UUID, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendReservation", ctx, reservationID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendReservation indicates an expected call of ExtendReservation.
+func (mr *MockRepositoryMockRecorder) ExtendReservation(ctx, reservationID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendReservation", reflect.TypeOf((*MockRepository)(nil).ExtendReservation), ctx, reservationID, expiresAt)
+}
+
+// GetAllStocks mocks base method.
+func (m *MockRepository) GetAllStocks(ctx context.Context) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllStocks", ctx)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllStocks indicates an expected call of GetAllStocks.
+func (mr *MockRepositoryMockRecorder) GetAllStocks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStocks", reflect.TypeOf((*MockRepository)(nil).GetAllStocks), ctx)
+}
+
+// GetItemByID mocks base method.
+func (m *MockRepository) GetItemByID(ctx context.Context, itemID uuid.UUID) (*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByID", ctx, itemID)
+	ret0, _ := ret[0].(*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByID indicates an expected call of GetItemByID.
+func (mr *MockRepositoryMockRecorder) GetItemByID(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockRepository)(nil).GetItemByID), ctx, itemID)
+}
+
+// GetItemStocks mocks base method.
+func (m *MockRepository) GetItemStocks(ctx context.Context, itemIDs []uuid.UUID) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemStocks", ctx, itemIDs)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemStocks indicates an expected call of GetItemStocks.
+func (mr *MockRepositoryMockRecorder) GetItemStocks(ctx, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemStocks", reflect.TypeOf((*MockRepository)(nil).GetItemStocks), ctx, itemIDs)
+}
+
+// GetItems mocks base method.
+func (m *MockRepository) GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, pq)
+	ret0, _ := ret[0].(*models.InventoryItemList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockRepositoryMockRecorder) GetItems(ctx, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockRepository)(nil).GetItems), ctx, pq)
+}
+
+// GetMovements mocks base method.
+func (m *MockRepository) GetMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovements", ctx, filter, pq)
+	ret0, _ := ret[0].(*models.InventoryMovementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovements indicates an expected call of GetMovements.
+func (mr *MockRepositoryMockRecorder) GetMovements(ctx, filter, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovements", reflect.TypeOf((*MockRepository)(nil).GetMovements), ctx, filter, pq)
+}
+
+// GetReservation mocks base method.
+func (m *MockRepository) GetReservation(ctx context.Context, reservationID uuid.UUID) (*models.InventoryReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", ctx, reservationID)
+	ret0, _ := ret[0].(*models.InventoryReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockRepositoryMockRecorder) GetReservation(ctx, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockRepository)(nil).GetReservation), ctx, reservationID)
+}
+
+// GetReservedQty mocks base method.
+func (m *MockRepository) GetReservedQty(ctx context.Context, itemID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservedQty", ctx, itemID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservedQty indicates an expected call of GetReservedQty.
+func (mr *MockRepositoryMockRecorder) GetReservedQty(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservedQty", reflect.TypeOf((*MockRepository)(nil).GetReservedQty), ctx, itemID)
+}
+
+// GetStockDrifts mocks base method.
+func (m *MockRepository) GetStockDrifts(ctx context.Context) ([]*models.StockDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockDrifts", ctx)
+	ret0, _ := ret[0].([]*models.StockDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockDrifts indicates an expected call of GetStockDrifts.
+func (mr *MockRepositoryMockRecorder) GetStockDrifts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockDrifts", reflect.TypeOf((*MockRepository)(nil).GetStockDrifts), ctx)
+}
+
+// GetThresholds mocks base method.
+func (m *MockRepository) GetThresholds(ctx context.Context, itemIDs []uuid.UUID) ([]*models.ItemThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThresholds", ctx, itemIDs)
+	ret0, _ := ret[0].([]*models.ItemThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThresholds indicates an expected call of GetThresholds.
+func (mr *MockRepositoryMockRecorder) GetThresholds(ctx, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThresholds", reflect.TypeOf((*MockRepository)(nil).GetThresholds), ctx, itemIDs)
+}
+
+// GetWarehouseByID mocks base method.
+func (m *MockRepository) GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseByID", ctx, warehouseID)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseByID indicates an expected call of GetWarehouseByID.
+func (mr *MockRepositoryMockRecorder) GetWarehouseByID(ctx, warehouseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseByID", reflect.TypeOf((*MockRepository)(nil).GetWarehouseByID), ctx, warehouseID)
+}
+
+// GetWarehouses mocks base method.
+func (m *MockRepository) GetWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouses", ctx)
+	ret0, _ := ret[0].([]*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouses indicates an expected call of GetWarehouses.
+func (mr *MockRepositoryMockRecorder) GetWarehouses(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouses", reflect.TypeOf((*MockRepository)(nil).GetWarehouses), ctx)
+}
+
+// ReconcileStock mocks base method.
+func (m *MockRepository) ReconcileStock(ctx context.Context) ([]*models.StockDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileStock", ctx)
+	ret0, _ := ret[0].([]*models.StockDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileStock indicates an expected call of ReconcileStock.
+func (mr *MockRepositoryMockRecorder) ReconcileStock(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileStock", reflect.TypeOf((*MockRepository)(nil).ReconcileStock), ctx)
+}
+
+// RemoveItems mocks base method.
+func (m *MockRepository) RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItems", ctx, items, change)
+	ret0, _ := ret[0].(*models.StockRemoval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveItems indicates an expected call of RemoveItems.
+func (mr *MockRepositoryMockRecorder) RemoveItems(ctx, items, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItems", reflect.TypeOf((*MockRepository)(nil).RemoveItems), ctx, items, change)
+}
+
+// SetThreshold mocks base method.
+func (m *MockRepository) SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetThreshold", ctx, threshold)
+	ret0, _ := ret[0].(*models.ItemThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetThreshold indicates an expected call of SetThreshold.
+func (mr *MockRepositoryMockRecorder) SetThreshold(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetThreshold", reflect.TypeOf((*MockRepository)(nil).SetThreshold), ctx, threshold)
+}
+
+// UpdateWarehouse mocks base method.
+func (m *MockRepository) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWarehouse", ctx, warehouse)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWarehouse indicates an expected call of UpdateWarehouse.
+func (mr *MockRepositoryMockRecorder) UpdateWarehouse(ctx, warehouse interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouse", reflect.TypeOf((*MockRepository)(nil).UpdateWarehouse), ctx, warehouse)
+}
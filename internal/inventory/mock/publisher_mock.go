@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: publisher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishLowStock mocks base method.
+func (m *MockPublisher) PublishLowStock(ctx context.Context, event *models.LowStockEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishLowStock", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishLowStock indicates an expected call of PublishLowStock.
+func (mr *MockPublisherMockRecorder) PublishLowStock(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishLowStock", reflect.TypeOf((*MockPublisher)(nil).PublishLowStock), ctx, event)
+}
@@ -35,7 +35,7 @@ func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
 	return m.recorder
 }
 
-// DeleteUserCtx mocks base method.
+// DeleteItemCtx mocks base method.
 func (m *MockRedisRepository) DeleteItemCtx(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteItemCtx", ctx, key)
@@ -43,8 +43,8 @@ func (m *MockRedisRepository) DeleteItemCtx(ctx context.Context, key string) err
 	return ret0
 }
 
-// DeleteUserCtx indicates an expected call of DeleteUserCtx.
-func (mr *MockRedisRepositoryMockRecorder) DeleteUserCtx(ctx, key interface{}) *gomock.Call {
+// DeleteItemCtx indicates an expected call of DeleteItemCtx.
+func (mr *MockRedisRepositoryMockRecorder) DeleteItemCtx(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemCtx", reflect.TypeOf((*MockRedisRepository)(nil).DeleteItemCtx), ctx, key)
 }
@@ -64,7 +64,21 @@ func (mr *MockRedisRepositoryMockRecorder) GetByIDCtx(ctx, key interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDCtx", reflect.TypeOf((*MockRedisRepository)(nil).GetByIDCtx), ctx, key)
 }
 
-// SetUserCtx mocks base method.
+// PublishItemsCtx mocks base method.
+func (m *MockRedisRepository) PublishItemsCtx(ctx context.Context, items []*models.InventoryItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishItemsCtx", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishItemsCtx indicates an expected call of PublishItemsCtx.
+func (mr *MockRedisRepositoryMockRecorder) PublishItemsCtx(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishItemsCtx", reflect.TypeOf((*MockRedisRepository)(nil).PublishItemsCtx), ctx, items)
+}
+
+// SetItemCtx mocks base method.
 func (m *MockRedisRepository) SetItemCtx(ctx context.Context, key string, seconds int, item *models.InventoryItem) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetItemCtx", ctx, key, seconds, item)
@@ -72,8 +86,23 @@ func (m *MockRedisRepository) SetItemCtx(ctx context.Context, key string, second
 	return ret0
 }
 
-// SetUserCtx indicates an expected call of SetUserCtx.
-func (mr *MockRedisRepositoryMockRecorder) SetUserCtx(ctx, key, seconds, item interface{}) *gomock.Call {
+// SetItemCtx indicates an expected call of SetItemCtx.
+func (mr *MockRedisRepositoryMockRecorder) SetItemCtx(ctx, key, seconds, item interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItemCtx", reflect.TypeOf((*MockRedisRepository)(nil).SetItemCtx), ctx, key, seconds, item)
 }
+
+// SubscribeItemsCtx mocks base method.
+func (m *MockRedisRepository) SubscribeItemsCtx(ctx context.Context) (<-chan *models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeItemsCtx", ctx)
+	ret0, _ := ret[0].(<-chan *models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeItemsCtx indicates an expected call of SubscribeItemsCtx.
+func (mr *MockRedisRepositoryMockRecorder) SubscribeItemsCtx(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeItemsCtx", reflect.TypeOf((*MockRedisRepository)(nil).SubscribeItemsCtx), ctx)
+}
@@ -7,8 +7,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	utils "github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -36,19 +38,138 @@ func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
 	return m.recorder
 }
 
-// AddItem mocks base method.
-func (m *MockUseCase) AddItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error) {
+// AddItems mocks base method.
+func (m *MockUseCase) AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddItem", ctx, item)
+	ret := m.ctrl.Call(m, "AddItems", ctx, items, change)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddItems indicates an expected call of AddItems.
+func (mr *MockUseCaseMockRecorder) AddItems(ctx, items, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItems", reflect.TypeOf((*MockUseCase)(nil).AddItems), ctx, items, change)
+}
+
+// AdjustStock mocks base method.
+func (m *MockUseCase) AdjustStock(ctx context.Context, itemID uuid.UUID, adjustment *models.StockAdjustment, actor string) (*models.InventoryItemDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", ctx, itemID, adjustment, actor)
+	ret0, _ := ret[0].(*models.InventoryItemDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockUseCaseMockRecorder) AdjustStock(ctx, itemID, adjustment, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockUseCase)(nil).AdjustStock), ctx, itemID, adjustment, actor)
+}
+
+// AllocateItems mocks base method.
+func (m *MockUseCase) AllocateItems(ctx context.Context, items []*models.InventoryItem, options models.AllocationOptions) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllocateItems", ctx, items, options)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].([]*models.InventoryItem)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AllocateItems indicates an expected call of AllocateItems.
+func (mr *MockUseCaseMockRecorder) AllocateItems(ctx, items, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocateItems", reflect.TypeOf((*MockUseCase)(nil).AllocateItems), ctx, items, options)
+}
+
+// CommitReservation mocks base method.
+func (m *MockUseCase) CommitReservation(ctx context.Context, reservationID, orderID uuid.UUID, actor string, options models.AllocationOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitReservation", ctx, reservationID, orderID, actor, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitReservation indicates an expected call of CommitReservation.
+func (mr *MockUseCaseMockRecorder) CommitReservation(ctx, reservationID, orderID, actor, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitReservation", reflect.TypeOf((*MockUseCase)(nil).CommitReservation), ctx, reservationID, orderID, actor, options)
+}
+
+// CreateWarehouse mocks base method.
+func (m *MockUseCase) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWarehouse", ctx, warehouse)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWarehouse indicates an expected call of CreateWarehouse.
+func (mr *MockUseCaseMockRecorder) CreateWarehouse(ctx, warehouse interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouse", reflect.TypeOf((*MockUseCase)(nil).CreateWarehouse), ctx, warehouse)
+}
+
+// DeleteWarehouse mocks base method.
+func (m *MockUseCase) DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWarehouse", ctx, warehouseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWarehouse indicates an expected call of DeleteWarehouse.
+func (mr *MockUseCaseMockRecorder) DeleteWarehouse(ctx, warehouseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWarehouse", reflect.TypeOf((*MockUseCase)(nil).DeleteWarehouse), ctx, warehouseID)
+}
+
+// ExportItems mocks base method.
+func (m *MockUseCase) ExportItems(ctx context.Context) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportItems", ctx)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportItems indicates an expected call of ExportItems.
+func (mr *MockUseCaseMockRecorder) ExportItems(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportItems", reflect.TypeOf((*MockUseCase)(nil).ExportItems), ctx)
+}
+
+// ExtendReservation mocks base method.
+func (m *MockUseCase) ExtendReservation(ctx context.Context, reservationID, orderID uuid.UUID, ttl time.Duration) (*models.InventoryReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendReservation", ctx, reservationID, orderID, ttl)
+	ret0, _ := ret[0].(*models.InventoryReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendReservation indicates an expected call of ExtendReservation.
+func (mr *MockUseCaseMockRecorder) ExtendReservation(ctx, reservationID, orderID, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendReservation", reflect.TypeOf((*MockUseCase)(nil).ExtendReservation), ctx, reservationID, orderID, ttl)
+}
+
+// GetAvailableItem mocks base method.
+func (m *MockUseCase) GetAvailableItem(ctx context.Context, item uuid.UUID) (*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableItem", ctx, item)
 	ret0, _ := ret[0].(*models.InventoryItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddItem indicates an expected call of AddItem.
-func (mr *MockUseCaseMockRecorder) AddItem(ctx, item interface{}) *gomock.Call {
+// GetAvailableItem indicates an expected call of GetAvailableItem.
+func (mr *MockUseCaseMockRecorder) GetAvailableItem(ctx, item interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockUseCase)(nil).AddItem), ctx, item)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableItem", reflect.TypeOf((*MockUseCase)(nil).GetAvailableItem), ctx, item)
 }
 
 // GetItemByID mocks base method.
@@ -66,17 +187,227 @@ func (mr *MockUseCaseMockRecorder) GetItemByID(ctx, item interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockUseCase)(nil).GetItemByID), ctx, item)
 }
 
-// RemoveItem mocks base method.
-func (m *MockUseCase) RemoveItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error) {
+// GetItemDetails mocks base method.
+func (m *MockUseCase) GetItemDetails(ctx context.Context, itemID uuid.UUID) (*models.InventoryItemDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveItem", ctx, item)
-	ret0, _ := ret[0].(*models.InventoryItem)
+	ret := m.ctrl.Call(m, "GetItemDetails", ctx, itemID)
+	ret0, _ := ret[0].(*models.InventoryItemDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemDetails indicates an expected call of GetItemDetails.
+func (mr *MockUseCaseMockRecorder) GetItemDetails(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemDetails", reflect.TypeOf((*MockUseCase)(nil).GetItemDetails), ctx, itemID)
+}
+
+// GetItemStocks mocks base method.
+func (m *MockUseCase) GetItemStocks(ctx context.Context, item uuid.UUID) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemStocks", ctx, item)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemStocks indicates an expected call of GetItemStocks.
+func (mr *MockUseCaseMockRecorder) GetItemStocks(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemStocks", reflect.TypeOf((*MockUseCase)(nil).GetItemStocks), ctx, item)
+}
+
+// GetItems mocks base method.
+func (m *MockUseCase) GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, pq)
+	ret0, _ := ret[0].(*models.InventoryItemList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockUseCaseMockRecorder) GetItems(ctx, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockUseCase)(nil).GetItems), ctx, pq)
+}
+
+// GetThreshold mocks base method.
+func (m *MockUseCase) GetThreshold(ctx context.Context, itemID uuid.UUID) (*models.ItemThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThreshold", ctx, itemID)
+	ret0, _ := ret[0].(*models.ItemThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThreshold indicates an expected call of GetThreshold.
+func (mr *MockUseCaseMockRecorder) GetThreshold(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThreshold", reflect.TypeOf((*MockUseCase)(nil).GetThreshold), ctx, itemID)
+}
+
+// GetWarehouseByID mocks base method.
+func (m *MockUseCase) GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseByID", ctx, warehouseID)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseByID indicates an expected call of GetWarehouseByID.
+func (mr *MockUseCaseMockRecorder) GetWarehouseByID(ctx, warehouseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseByID", reflect.TypeOf((*MockUseCase)(nil).GetWarehouseByID), ctx, warehouseID)
+}
+
+// GetWarehouses mocks base method.
+func (m *MockUseCase) GetWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouses", ctx)
+	ret0, _ := ret[0].([]*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouses indicates an expected call of GetWarehouses.
+func (mr *MockUseCaseMockRecorder) GetWarehouses(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouses", reflect.TypeOf((*MockUseCase)(nil).GetWarehouses), ctx)
+}
+
+// ImportItems mocks base method.
+func (m *MockUseCase) ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportItems", ctx, rows, change)
+	ret0, _ := ret[0].(*models.InventoryImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportItems indicates an expected call of ImportItems.
+func (mr *MockUseCaseMockRecorder) ImportItems(ctx, rows, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportItems", reflect.TypeOf((*MockUseCase)(nil).ImportItems), ctx, rows, change)
+}
+
+// ListMovements mocks base method.
+func (m *MockUseCase) ListMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMovements", ctx, filter, pq)
+	ret0, _ := ret[0].(*models.InventoryMovementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMovements indicates an expected call of ListMovements.
+func (mr *MockUseCaseMockRecorder) ListMovements(ctx, filter, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMovements", reflect.TypeOf((*MockUseCase)(nil).ListMovements), ctx, filter, pq)
+}
+
+// ReconcileStock mocks base method.
+func (m *MockUseCase) ReconcileStock(ctx context.Context, apply bool) ([]*models.StockDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileStock", ctx, apply)
+	ret0, _ := ret[0].([]*models.StockDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileStock indicates an expected call of ReconcileStock.
+func (mr *MockUseCaseMockRecorder) ReconcileStock(ctx, apply interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileStock", reflect.TypeOf((*MockUseCase)(nil).ReconcileStock), ctx, apply)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockUseCase) ReleaseReservation(ctx context.Context, reservationID, orderID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, reservationID, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockUseCaseMockRecorder) ReleaseReservation(ctx, reservationID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockUseCase)(nil).ReleaseReservation), ctx, reservationID, orderID)
+}
+
+// RemoveItems mocks base method.
+func (m *MockUseCase) RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItems", ctx, items, change)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].([]*models.InventoryItem)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RemoveItems indicates an expected call of RemoveItems.
+func (mr *MockUseCaseMockRecorder) RemoveItems(ctx, items, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItems", reflect.TypeOf((*MockUseCase)(nil).RemoveItems), ctx, items, change)
+}
+
+// Reserve mocks base method.
+func (m *MockUseCase) Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, reservation)
+	ret0, _ := ret[0].([]*models.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockUseCaseMockRecorder) Reserve(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockUseCase)(nil).Reserve), ctx, reservation)
+}
+
+// SetThreshold mocks base method.
+func (m *MockUseCase) SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetThreshold", ctx, threshold)
+	ret0, _ := ret[0].(*models.ItemThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetThreshold indicates an expected call of SetThreshold.
+func (mr *MockUseCaseMockRecorder) SetThreshold(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetThreshold", reflect.TypeOf((*MockUseCase)(nil).SetThreshold), ctx, threshold)
+}
+
+// UpdateWarehouse mocks base method.
+func (m *MockUseCase) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWarehouse", ctx, warehouse)
+	ret0, _ := ret[0].(*models.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWarehouse indicates an expected call of UpdateWarehouse.
+func (mr *MockUseCaseMockRecorder) UpdateWarehouse(ctx, warehouse interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouse", reflect.TypeOf((*MockUseCase)(nil).UpdateWarehouse), ctx, warehouse)
+}
+
+// WatchItems mocks base method.
+func (m *MockUseCase) WatchItems(ctx context.Context, itemIDs []uuid.UUID) (<-chan *models.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchItems", ctx, itemIDs)
+	ret0, _ := ret[0].(<-chan *models.InventoryItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RemoveItem indicates an expected call of RemoveItem.
-func (mr *MockUseCaseMockRecorder) RemoveItem(ctx, item interface{}) *gomock.Call {
+// WatchItems indicates an expected call of WatchItems.
+func (mr *MockUseCaseMockRecorder) WatchItems(ctx, itemIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockUseCase)(nil).RemoveItem), ctx, item)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchItems", reflect.TypeOf((*MockUseCase)(nil).WatchItems), ctx, itemIDs)
 }
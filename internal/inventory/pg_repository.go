@@ -0,0 +1,36 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository_mock.go -package mock
+package inventory
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Inventory repository
+type Repository interface {
+	GetItemByID(ctx context.Context, itemID uuid.UUID) (*models.InventoryItem, error)
+	GetItemStocks(ctx context.Context, itemIDs []uuid.UUID) ([]*models.InventoryItem, error)
+	AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error)
+	RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error)
+	CreateReservation(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error)
+	GetReservation(ctx context.Context, reservationID uuid.UUID) (*models.InventoryReservation, error)
+	CommitReservation(ctx context.Context, reservationID uuid.UUID, stocks []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error)
+	ExtendReservation(ctx context.Context, reservationID uuid.UUID, expiresAt time.Time) error
+	DeleteReservation(ctx context.Context, reservationID uuid.UUID) error
+	GetReservedQty(ctx context.Context, itemID uuid.UUID) (int, error)
+	GetMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error)
+	GetStockDrifts(ctx context.Context) ([]*models.StockDrift, error)
+	ReconcileStock(ctx context.Context) ([]*models.StockDrift, error)
+	GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error)
+	GetAllStocks(ctx context.Context) ([]*models.InventoryItem, error)
+	GetThresholds(ctx context.Context, itemIDs []uuid.UUID) ([]*models.ItemThreshold, error)
+	SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error)
+	CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]*models.Warehouse, error)
+	DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error
+}
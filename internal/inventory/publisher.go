@@ -0,0 +1,12 @@
+//go:generate mockgen -source publisher.go -destination mock/publisher_mock.go -package mock
+package inventory
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+// Inventory events publisher
+type Publisher interface {
+	PublishLowStock(ctx context.Context, event *models.LowStockEvent) error
+}
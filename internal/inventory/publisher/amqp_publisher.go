@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publishes inventory events to notification queue
+type inventoryPublisher struct {
+	amqpChannel *amqp.Channel
+	amqpQueue   *amqp.Queue
+}
+
+func NewInventoryPublisher(amqpChannel *amqp.Channel, amqpQueue *amqp.Queue) inventory.Publisher {
+	return &inventoryPublisher{amqpChannel: amqpChannel, amqpQueue: amqpQueue}
+}
+
+func (p *inventoryPublisher) PublishLowStock(ctx context.Context, event *models.LowStockEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryPublisher.PublishLowStock")
+	defer span.Finish()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "inventoryPublisher.PublishLowStock.json.Marshal")
+	}
+
+	err = p.amqpChannel.PublishWithContext(ctx,
+		"",
+		p.amqpQueue.Name,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Type:        models.LowStockEventType,
+			Body:        body,
+		})
+	if err != nil {
+		return errors.Wrap(err, "inventoryPublisher.PublishLowStock.PublishWithContext")
+	}
+	return nil
+}
@@ -10,4 +10,6 @@ type RedisRepository interface {
 	GetByIDCtx(ctx context.Context, key string) (*models.InventoryItem, error)
 	SetItemCtx(ctx context.Context, key string, seconds int, item *models.InventoryItem) error
 	DeleteItemCtx(ctx context.Context, key string) error
+	PublishItemsCtx(ctx context.Context, items []*models.InventoryItem) error
+	SubscribeItemsCtx(ctx context.Context) (<-chan *models.InventoryItem, error)
 }
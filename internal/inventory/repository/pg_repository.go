@@ -0,0 +1,664 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Inventory Repository
+type inventoryRepo struct {
+	db *sqlx.DB
+}
+
+// Inventory repository constructor
+func NewInventoryRepository(db *sqlx.DB) inventory.Repository {
+	return &inventoryRepo{db: db}
+}
+
+// Get item stock summed over warehouses, returns nil when item is unknown
+func (r *inventoryRepo) GetItemByID(ctx context.Context, itemID uuid.UUID) (*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetItemByID")
+	defer span.Finish()
+
+	item := &models.InventoryItem{}
+	if err := r.db.QueryRowxContext(ctx, getItemByID, itemID).StructScan(item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "inventoryRepo.GetItemByID.QueryRowxContext")
+	}
+	return item, nil
+}
+
+// Get non-empty stocks of items in every warehouse
+func (r *inventoryRepo) GetItemStocks(ctx context.Context, itemIDs []uuid.UUID) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetItemStocks")
+	defer span.Finish()
+
+	stocks := make([]*models.InventoryItem, 0)
+	if len(itemIDs) == 0 {
+		return stocks, nil
+	}
+
+	placeholders := make([]string, 0, len(itemIDs))
+	args := make([]interface{}, 0, len(itemIDs))
+	for i, itemID := range itemIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, itemID)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, fmt.Sprintf(getItemStocks, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetItemStocks.QueryxContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stock := &models.InventoryItem{}
+		if err = rows.StructScan(stock); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.GetItemStocks.StructScan")
+		}
+		stocks = append(stocks, stock)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetItemStocks.rows.Err")
+	}
+	return stocks, nil
+}
+
+// Add quantities to items and write ledger entries in one transaction, returns resulting items
+func (r *inventoryRepo) AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.AddItems")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.AddItems.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	resultItems := make([]*models.InventoryItem, 0, len(items))
+	for _, item := range sortItems(items) {
+		resultItem := &models.InventoryItem{}
+		if err = tx.QueryRowxContext(ctx, addItem, item.UUID, item.WarehouseID, item.Qty).StructScan(resultItem); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.AddItems.QueryRowxContext")
+		}
+		if err = writeMovement(ctx, tx, item, item.Qty, change); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.AddItems.writeMovement")
+		}
+		resultItems = append(resultItems, resultItem)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.AddItems.Commit")
+	}
+	return resultItems, nil
+}
+
+// Remove quantities of all items and write ledger entries in one transaction, returns resulting items
+// with item levels seen inside transaction. When some item is short nothing is changed
+// and short items with available quantity are returned.
+func (r *inventoryRepo) RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.RemoveItems")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RemoveItems.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	removal, err := removeItems(ctx, tx, items, change)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RemoveItems.removeItems")
+	}
+	if len(removal.Short) > 0 {
+		return removal, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.RemoveItems.Commit")
+	}
+	return removal, nil
+}
+
+// Hold items for order in one transaction. Stocks of items stay locked until reservation is written,
+// so concurrent reservations and removals can not hold more than there is in stock.
+// When some item is short nothing is held and short items with available quantity are returned.
+func (r *inventoryRepo) CreateReservation(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.CreateReservation")
+	defer span.Finish()
+
+	// Expired reservations do not hold stock anymore
+	if _, err := r.db.ExecContext(ctx, deleteExpiredReservations); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.ExecContext.deleteExpiredReservations")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	sorted := sortItems(reservation.Items)
+	shortItems := make([]*models.InventoryItem, 0)
+	for _, item := range sorted {
+		stocks, reserved, err := lockItemStocks(ctx, tx, item.UUID)
+		if err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.lockItemStocks")
+		}
+		available := -reserved
+		for _, stock := range stocks {
+			available += stock.Qty
+		}
+		if available < 0 {
+			available = 0
+		}
+		if available < item.Qty {
+			shortItems = append(shortItems, &models.InventoryItem{UUID: item.UUID, Qty: available})
+		}
+	}
+	if len(shortItems) > 0 {
+		return shortItems, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, createReservation, reservation.ReservationID, reservation.OrderID, reservation.ExpiresAt); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.ExecContext.createReservation")
+	}
+	for _, item := range sorted {
+		if _, err = tx.ExecContext(ctx, createReservationItem, reservation.ReservationID, item.UUID, item.Qty); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.ExecContext.createReservationItem")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CreateReservation.Commit")
+	}
+	return nil, nil
+}
+
+// Get not expired reservation with its items, returns nil when reservation is missing
+func (r *inventoryRepo) GetReservation(ctx context.Context, reservationID uuid.UUID) (*models.InventoryReservation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetReservation")
+	defer span.Finish()
+
+	reservation := &models.InventoryReservation{}
+	if err := r.db.GetContext(ctx, reservation, getReservation, reservationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "inventoryRepo.GetReservation.GetContext")
+	}
+
+	reservation.Items = make([]*models.InventoryItem, 0)
+	if err := r.db.SelectContext(ctx, &reservation.Items, getReservationItems, reservationID); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetReservation.SelectContext")
+	}
+	return reservation, nil
+}
+
+// Drop reservation and take stock out in one transaction, so reserved stock is either still held or taken.
+// Missing or expired reservation is reported as sql.ErrNoRows. When some stock is short
+// nothing is changed and short items with available quantity are returned.
+func (r *inventoryRepo) CommitReservation(ctx context.Context, reservationID uuid.UUID, stocks []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.CommitReservation")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CommitReservation.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var lockedID uuid.UUID
+	if err = tx.GetContext(ctx, &lockedID, getReservationForUpdate, reservationID); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CommitReservation.GetContext")
+	}
+	if _, err = tx.ExecContext(ctx, deleteReservation, reservationID); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CommitReservation.ExecContext.deleteReservation")
+	}
+
+	removal, err := removeItems(ctx, tx, stocks, change)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CommitReservation.removeItems")
+	}
+	if len(removal.Short) > 0 {
+		return removal, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CommitReservation.Commit")
+	}
+	return removal, nil
+}
+
+// Move expiration of not expired reservation, missing or expired reservation is reported as sql.ErrNoRows
+func (r *inventoryRepo) ExtendReservation(ctx context.Context, reservationID uuid.UUID, expiresAt time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.ExtendReservation")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, extendReservation, reservationID, expiresAt)
+	if err != nil {
+		return errors.Wrap(err, "inventoryRepo.ExtendReservation.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "inventoryRepo.ExtendReservation.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "inventoryRepo.ExtendReservation.RowsAffected")
+	}
+	return nil
+}
+
+// Delete reservation with its items
+func (r *inventoryRepo) DeleteReservation(ctx context.Context, reservationID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.DeleteReservation")
+	defer span.Finish()
+
+	if _, err := r.db.ExecContext(ctx, deleteReservation, reservationID); err != nil {
+		return errors.Wrap(err, "inventoryRepo.DeleteReservation.ExecContext")
+	}
+	return nil
+}
+
+// Sum of item quantities held by not expired reservations
+func (r *inventoryRepo) GetReservedQty(ctx context.Context, itemID uuid.UUID) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetReservedQty")
+	defer span.Finish()
+
+	var reserved int
+	if err := r.db.GetContext(ctx, &reserved, getReservedQty, itemID); err != nil {
+		return 0, errors.Wrap(err, "inventoryRepo.GetReservedQty.GetContext")
+	}
+	return reserved, nil
+}
+
+// Get ledger entries page, newest first
+func (r *inventoryRepo) GetMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetMovements")
+	defer span.Finish()
+
+	where, args := buildMovementFilter(filter)
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getMovementsCount+where, args...); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetMovements.GetContext.totalCount")
+	}
+
+	movements := make([]*models.InventoryMovement, 0)
+	if totalCount > 0 {
+		query := fmt.Sprintf("%s%s ORDER BY created_at DESC, movement_id OFFSET $%d LIMIT $%d", getMovements, where, len(args)+1, len(args)+2)
+		args = append(args, pq.GetOffset(), pq.GetLimit())
+		if err := r.db.SelectContext(ctx, &movements, query, args...); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.GetMovements.SelectContext")
+		}
+	}
+
+	return &models.InventoryMovementList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Movements:  movements,
+	}, nil
+}
+
+// Get items which stored stock differs from stock rebuilt from ledger
+func (r *inventoryRepo) GetStockDrifts(ctx context.Context) ([]*models.StockDrift, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetStockDrifts")
+	defer span.Finish()
+
+	drifts := make([]*models.StockDrift, 0)
+	if err := r.db.SelectContext(ctx, &drifts, getStockDrifts); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetStockDrifts.SelectContext")
+	}
+	return drifts, nil
+}
+
+// Overwrite drifted stock with stock rebuilt from ledger, returns fixed drifts
+func (r *inventoryRepo) ReconcileStock(ctx context.Context) ([]*models.StockDrift, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.ReconcileStock")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ReconcileStock.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	// Stock changes wait until reconcile is done
+	if _, err = tx.ExecContext(ctx, lockStock); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ReconcileStock.ExecContext.lockStock")
+	}
+
+	drifts := make([]*models.StockDrift, 0)
+	if err = tx.SelectContext(ctx, &drifts, getStockDrifts); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ReconcileStock.SelectContext")
+	}
+	for _, drift := range drifts {
+		if _, err = tx.ExecContext(ctx, reconcileItem, drift.ItemID, drift.WarehouseID, drift.LedgerQty); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.ReconcileStock.ExecContext.reconcileItem")
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.ReconcileStock.Commit")
+	}
+	return drifts, nil
+}
+
+// Get own thresholds of items, items without threshold are skipped
+func (r *inventoryRepo) GetThresholds(ctx context.Context, itemIDs []uuid.UUID) ([]*models.ItemThreshold, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetThresholds")
+	defer span.Finish()
+
+	thresholds := make([]*models.ItemThreshold, 0)
+	if len(itemIDs) == 0 {
+		return thresholds, nil
+	}
+
+	placeholders := make([]string, 0, len(itemIDs))
+	args := make([]interface{}, 0, len(itemIDs))
+	for i, itemID := range itemIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, itemID)
+	}
+
+	if err := r.db.SelectContext(ctx, &thresholds, fmt.Sprintf(getThresholds, strings.Join(placeholders, ", ")), args...); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetThresholds.SelectContext")
+	}
+	return thresholds, nil
+}
+
+// Get page of item stocks summed over warehouses
+func (r *inventoryRepo) GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetItems")
+	defer span.Finish()
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getItemsCount); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetItems.GetContext.totalCount")
+	}
+
+	stocks := make([]*models.InventoryItem, 0)
+	if totalCount > 0 {
+		if err := r.db.SelectContext(ctx, &stocks, getItems, pq.GetOffset(), pq.GetLimit()); err != nil {
+			return nil, errors.Wrap(err, "inventoryRepo.GetItems.SelectContext")
+		}
+	}
+
+	items := make([]*models.InventoryItemDetails, 0, len(stocks))
+	for _, stock := range stocks {
+		items = append(items, &models.InventoryItemDetails{ItemID: stock.UUID, Qty: stock.Qty})
+	}
+	return &models.InventoryItemList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Items:      items,
+	}, nil
+}
+
+// Get stocks of all items in all warehouses
+func (r *inventoryRepo) GetAllStocks(ctx context.Context) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetAllStocks")
+	defer span.Finish()
+
+	stocks := make([]*models.InventoryItem, 0)
+	if err := r.db.SelectContext(ctx, &stocks, getAllStocks); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetAllStocks.SelectContext")
+	}
+	return stocks, nil
+}
+
+// Create or replace item threshold
+func (r *inventoryRepo) SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.SetThreshold")
+	defer span.Finish()
+
+	t := &models.ItemThreshold{}
+	if err := r.db.QueryRowxContext(ctx, setThreshold, threshold.ItemID, threshold.Threshold).StructScan(t); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.SetThreshold.StructScan")
+	}
+	return t, nil
+}
+
+// Create warehouse
+func (r *inventoryRepo) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.CreateWarehouse")
+	defer span.Finish()
+
+	w := &models.Warehouse{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		createWarehouse,
+		warehouse.WarehouseID,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Latitude,
+		warehouse.Longitude,
+	).StructScan(w); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.CreateWarehouse.StructScan")
+	}
+	return w, nil
+}
+
+// Update warehouse
+func (r *inventoryRepo) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.UpdateWarehouse")
+	defer span.Finish()
+
+	w := &models.Warehouse{}
+	if err := r.db.QueryRowxContext(
+		ctx,
+		updateWarehouse,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Latitude,
+		warehouse.Longitude,
+		warehouse.WarehouseID,
+	).StructScan(w); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.UpdateWarehouse.StructScan")
+	}
+	return w, nil
+}
+
+// Get warehouse by id
+func (r *inventoryRepo) GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetWarehouseByID")
+	defer span.Finish()
+
+	w := &models.Warehouse{}
+	if err := r.db.QueryRowxContext(ctx, getWarehouseByID, warehouseID).StructScan(w); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetWarehouseByID.StructScan")
+	}
+	return w, nil
+}
+
+// Get all warehouses
+func (r *inventoryRepo) GetWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.GetWarehouses")
+	defer span.Finish()
+
+	warehouses := make([]*models.Warehouse, 0)
+	if err := r.db.SelectContext(ctx, &warehouses, getWarehouses); err != nil {
+		return nil, errors.Wrap(err, "inventoryRepo.GetWarehouses.SelectContext")
+	}
+	return warehouses, nil
+}
+
+// Delete warehouse without stock
+func (r *inventoryRepo) DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRepo.DeleteWarehouse")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, deleteWarehouse, warehouseID)
+	if err != nil {
+		return errors.Wrap(err, "inventoryRepo.DeleteWarehouse.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "inventoryRepo.DeleteWarehouse.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(sql.ErrNoRows, "inventoryRepo.DeleteWarehouse.RowsAffected")
+	}
+	return nil
+}
+
+// Remove quantities of all items and write ledger entries inside transaction, returns resulting items
+// with item levels in all warehouses. Stock held by active reservations is not removed. When some item is short
+// nothing is removed and short items are returned, with quantity in warehouse or, without warehouse,
+// quantity not held by reservations.
+func removeItems(ctx context.Context, tx *sqlx.Tx, items []*models.InventoryItem, change models.StockChange) (*models.StockRemoval, error) {
+	type stockKey struct {
+		item, warehouse uuid.UUID
+	}
+
+	// Lock every stock of items in a stable order so concurrent changes do not deadlock
+	sorted := sortItems(items)
+	itemIDs := make([]uuid.UUID, 0, len(sorted))
+	stored := make(map[stockKey]int)
+	inStock := make(map[uuid.UUID]int)
+	available := make(map[uuid.UUID]int)
+	for _, item := range sorted {
+		if _, ok := available[item.UUID]; ok {
+			continue
+		}
+		stocks, reserved, err := lockItemStocks(ctx, tx, item.UUID)
+		if err != nil {
+			return nil, err
+		}
+		itemIDs = append(itemIDs, item.UUID)
+		available[item.UUID] = -reserved
+		for _, stock := range stocks {
+			stored[stockKey{item: stock.UUID, warehouse: stock.WarehouseID}] = stock.Qty
+			inStock[item.UUID] += stock.Qty
+		}
+		available[item.UUID] += inStock[item.UUID]
+	}
+
+	shortItems := make([]*models.InventoryItem, 0)
+	removed := make(map[uuid.UUID]int, len(itemIDs))
+	for _, item := range sorted {
+		if qty := stored[stockKey{item: item.UUID, warehouse: item.WarehouseID}]; qty < item.Qty {
+			shortItems = append(shortItems, &models.InventoryItem{UUID: item.UUID, WarehouseID: item.WarehouseID, Qty: qty})
+		}
+		removed[item.UUID] += item.Qty
+	}
+	if len(shortItems) > 0 {
+		return &models.StockRemoval{Short: shortItems}, nil
+	}
+	for _, itemID := range itemIDs {
+		if removed[itemID] > available[itemID] {
+			qty := available[itemID]
+			if qty < 0 {
+				qty = 0
+			}
+			shortItems = append(shortItems, &models.InventoryItem{UUID: itemID, Qty: qty})
+		}
+	}
+	if len(shortItems) > 0 {
+		return &models.StockRemoval{Short: shortItems}, nil
+	}
+
+	resultItems := make([]*models.InventoryItem, 0, len(sorted))
+	for _, item := range sorted {
+		resultItem := &models.InventoryItem{}
+		if err := tx.QueryRowxContext(ctx, removeItem, item.Qty, item.UUID, item.WarehouseID).StructScan(resultItem); err != nil {
+			return nil, errors.Wrap(err, "QueryRowxContext.removeItem")
+		}
+		if err := writeMovement(ctx, tx, item, -item.Qty, change); err != nil {
+			return nil, errors.Wrap(err, "writeMovement")
+		}
+		resultItems = append(resultItems, resultItem)
+	}
+
+	levels := make([]*models.StockLevel, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		levels = append(levels, &models.StockLevel{ItemID: itemID, Before: inStock[itemID], After: inStock[itemID] - removed[itemID]})
+	}
+	return &models.StockRemoval{Items: resultItems, Levels: levels}, nil
+}
+
+// Lock item stock in every warehouse, returns stocks with quantity held by active reservations
+func lockItemStocks(ctx context.Context, tx *sqlx.Tx, itemID uuid.UUID) ([]*models.InventoryItem, int, error) {
+	stocks := make([]*models.InventoryItem, 0)
+	if err := tx.SelectContext(ctx, &stocks, getItemStocksForUpdate, itemID); err != nil {
+		return nil, 0, errors.Wrap(err, "SelectContext.getItemStocksForUpdate")
+	}
+
+	var reserved int
+	if err := tx.GetContext(ctx, &reserved, getReservedQty, itemID); err != nil {
+		return nil, 0, errors.Wrap(err, "GetContext.getReservedQty")
+	}
+	return stocks, reserved, nil
+}
+
+// Append ledger entry of item stock change
+func writeMovement(ctx context.Context, tx *sqlx.Tx, item *models.InventoryItem, delta int, change models.StockChange) error {
+	_, err := tx.ExecContext(ctx, createMovement, item.UUID, item.WarehouseID, delta, change.Reason, change.OrderID, change.Actor)
+	return err
+}
+
+// Build where clause of movement filter with numbered placeholders
+func buildMovementFilter(filter *models.InventoryMovementFilter) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ItemID != nil {
+		add("item_id = $%d", *filter.ItemID)
+	}
+	if filter.WarehouseID != nil {
+		add("warehouse_id = $%d", *filter.WarehouseID)
+	}
+	if filter.OrderID != nil {
+		add("order_id = $%d", *filter.OrderID)
+	}
+	if filter.Reason != "" {
+		add("reason = $%d", filter.Reason)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Copy of items ordered by item and warehouse id
+func sortItems(items []*models.InventoryItem) []*models.InventoryItem {
+	sorted := make([]*models.InventoryItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		if c := bytes.Compare(sorted[i].UUID[:], sorted[j].UUID[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(sorted[i].WarehouseID[:], sorted[j].WarehouseID[:]) < 0
+	})
+	return sorted
+}
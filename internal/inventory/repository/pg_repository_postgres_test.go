@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/stdlib" // pgx driver
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+const testMigrationsDir = "../../../db/migrations"
+
+// Connect to PostgreSQL from TEST_POSTGRES_DSN with all migrations applied, test is skipped when it is not set.
+// Migrations drop and recreate tables, so DSN has to point to a disposable database
+func newTestPostgres(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sqlx.Connect("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob(filepath.Join(testMigrationsDir, "*.up.sql"))
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		query, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		_, err = db.Exec(string(query))
+		require.NoError(t, err, migration)
+	}
+	return db
+}
+
+func TestInventoryRepo_RemoveItemsConcurrently(t *testing.T) {
+	db := newTestPostgres(t)
+	inventoryRepo := NewInventoryRepository(db)
+	ctx := context.Background()
+
+	const (
+		stored   = 10
+		reserved = 2
+		removals = 20
+	)
+	warehouse := &models.Warehouse{WarehouseID: uuid.New(), Name: "Main"}
+	_, err := inventoryRepo.CreateWarehouse(ctx, warehouse)
+	require.NoError(t, err)
+	itemID := uuid.New()
+	_, err = inventoryRepo.AddItems(ctx, []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouse.WarehouseID, Qty: stored}},
+		models.StockChange{Reason: models.InventoryMovementReasonReceipt, Actor: "test"})
+	require.NoError(t, err)
+	shortItems, err := inventoryRepo.CreateReservation(ctx, &models.InventoryReservation{
+		ReservationID: uuid.New(),
+		OrderID:       uuid.New(),
+		Items:         []*models.InventoryItem{{UUID: itemID, Qty: reserved}},
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Empty(t, shortItems)
+
+	var wg sync.WaitGroup
+	errs := make([]error, removals)
+	results := make([]*models.StockRemoval, removals)
+	for i := 0; i < removals; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items := []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouse.WarehouseID, Qty: 1}}
+			results[i], errs[i] = inventoryRepo.RemoveItems(ctx, items, models.StockChange{Reason: models.InventoryMovementReasonAdjustment, Actor: "test"})
+		}(i)
+	}
+	wg.Wait()
+
+	removed := 0
+	for i := range results {
+		require.NoError(t, errs[i])
+		if len(results[i].Short) > 0 {
+			continue
+		}
+		require.Len(t, results[i].Items, 1)
+		require.GreaterOrEqual(t, results[i].Items[0].Qty, reserved)
+		require.Equal(t, results[i].Items[0].Qty, results[i].Levels[0].After)
+		require.Equal(t, results[i].Levels[0].Before-1, results[i].Levels[0].After)
+		removed++
+	}
+	require.Equal(t, stored-reserved, removed)
+	stocks, err := inventoryRepo.GetItemStocks(ctx, []uuid.UUID{itemID})
+	require.NoError(t, err)
+	require.Len(t, stocks, 1)
+	require.Equal(t, reserved, stocks[0].Qty)
+
+	var delta int
+	require.NoError(t, db.Get(&delta, `SELECT SUM(delta) FROM inventory_movements WHERE item_id = $1`, itemID))
+	require.Equal(t, reserved, delta)
+}
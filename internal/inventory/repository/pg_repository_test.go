@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+func TestInventoryRepo_GetItemByID(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	t.Run("GetItemByID", func(t *testing.T) {
+		itemID := uuid.New()
+		rows := sqlmock.NewRows([]string{"item_id", "qty"}).AddRow(itemID, 5)
+		mock.ExpectQuery(getItemByID).WithArgs(itemID).WillReturnRows(rows)
+
+		item, err := inventoryRepo.GetItemByID(context.Background(), itemID)
+		require.NoError(t, err)
+		require.Equal(t, itemID, item.UUID)
+		require.Equal(t, 5, item.Qty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		itemID := uuid.New()
+		mock.ExpectQuery(getItemByID).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"item_id", "qty"}))
+
+		item, err := inventoryRepo.GetItemByID(context.Background(), itemID)
+		require.NoError(t, err)
+		require.Nil(t, item)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInventoryRepo_RemoveItems(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	itemID, warehouseID, orderID := uuid.New(), uuid.New(), uuid.New()
+	items := []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouseID, Qty: 3}}
+	change := models.StockChange{Reason: models.InventoryMovementReasonOrder, OrderID: &orderID, Actor: "scheduler"}
+
+	t.Run("NotEnough", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 2))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectRollback()
+
+		removal, err := inventoryRepo.RemoveItems(context.Background(), items, change)
+		require.NoError(t, err)
+		require.Empty(t, removal.Items)
+		require.Len(t, removal.Short, 1)
+		require.Equal(t, 2, removal.Short[0].Qty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Reserved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 5))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+		mock.ExpectRollback()
+
+		removal, err := inventoryRepo.RemoveItems(context.Background(), items, change)
+		require.NoError(t, err)
+		require.Empty(t, removal.Items)
+		require.Len(t, removal.Short, 1)
+		require.Equal(t, uuid.Nil, removal.Short[0].WarehouseID)
+		require.Equal(t, 2, removal.Short[0].Qty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RemoveItems", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 5))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectQuery(removeItem).WithArgs(3, itemID, warehouseID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 2))
+		mock.ExpectExec(createMovement).WithArgs(itemID, warehouseID, -3, change.Reason, change.OrderID, change.Actor).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		removal, err := inventoryRepo.RemoveItems(context.Background(), items, change)
+		require.NoError(t, err)
+		require.Empty(t, removal.Short)
+		require.Len(t, removal.Items, 1)
+		require.Equal(t, warehouseID, removal.Items[0].WarehouseID)
+		require.Equal(t, 2, removal.Items[0].Qty)
+		require.Equal(t, []*models.StockLevel{{ItemID: itemID, Before: 5, After: 2}}, removal.Levels)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInventoryRepo_CreateReservation(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	itemID, warehouseID := uuid.New(), uuid.New()
+	reservation := &models.InventoryReservation{
+		ReservationID: uuid.New(),
+		OrderID:       uuid.New(),
+		Items:         []*models.InventoryItem{{UUID: itemID, Qty: 3}},
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	t.Run("NotEnough", func(t *testing.T) {
+		mock.ExpectExec(deleteExpiredReservations).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 5))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3))
+		mock.ExpectRollback()
+
+		shortItems, err := inventoryRepo.CreateReservation(context.Background(), reservation)
+		require.NoError(t, err)
+		require.Len(t, shortItems, 1)
+		require.Equal(t, 2, shortItems[0].Qty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CreateReservation", func(t *testing.T) {
+		mock.ExpectExec(deleteExpiredReservations).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectBegin()
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 5))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(2))
+		mock.ExpectExec(createReservation).WithArgs(reservation.ReservationID, reservation.OrderID, reservation.ExpiresAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(createReservationItem).WithArgs(reservation.ReservationID, itemID, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		shortItems, err := inventoryRepo.CreateReservation(context.Background(), reservation)
+		require.NoError(t, err)
+		require.Empty(t, shortItems)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInventoryRepo_CommitReservation(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	reservationID, itemID, warehouseID, orderID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	stocks := []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouseID, Qty: 3}}
+	change := models.StockChange{Reason: models.InventoryMovementReasonOrder, OrderID: &orderID, Actor: "scheduler"}
+
+	t.Run("Expired", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getReservationForUpdate).WithArgs(reservationID).WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}))
+		mock.ExpectRollback()
+
+		_, err := inventoryRepo.CommitReservation(context.Background(), reservationID, stocks, change)
+		require.True(t, errors.Is(err, sql.ErrNoRows))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotEnough", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getReservationForUpdate).WithArgs(reservationID).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}).AddRow(reservationID))
+		mock.ExpectExec(deleteReservation).WithArgs(reservationID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 2))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectRollback()
+
+		removal, err := inventoryRepo.CommitReservation(context.Background(), reservationID, stocks, change)
+		require.NoError(t, err)
+		require.Len(t, removal.Short, 1)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CommitReservation", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(getReservationForUpdate).WithArgs(reservationID).
+			WillReturnRows(sqlmock.NewRows([]string{"reservation_id"}).AddRow(reservationID))
+		mock.ExpectExec(deleteReservation).WithArgs(reservationID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getItemStocksForUpdate).WithArgs(itemID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 5))
+		mock.ExpectQuery(getReservedQty).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectQuery(removeItem).WithArgs(3, itemID, warehouseID).
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "warehouse_id", "qty"}).AddRow(itemID, warehouseID, 2))
+		mock.ExpectExec(createMovement).WithArgs(itemID, warehouseID, -3, change.Reason, change.OrderID, change.Actor).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		removal, err := inventoryRepo.CommitReservation(context.Background(), reservationID, stocks, change)
+		require.NoError(t, err)
+		require.Empty(t, removal.Short)
+		require.Len(t, removal.Items, 1)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInventoryRepo_DeleteWarehouse(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	t.Run("DeleteWarehouse", func(t *testing.T) {
+		warehouseID := uuid.New()
+		mock.ExpectExec(deleteWarehouse).WithArgs(warehouseID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := inventoryRepo.DeleteWarehouse(context.Background(), warehouseID)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("HoldsStock", func(t *testing.T) {
+		warehouseID := uuid.New()
+		mock.ExpectExec(deleteWarehouse).WithArgs(warehouseID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := inventoryRepo.DeleteWarehouse(context.Background(), warehouseID)
+		require.True(t, errors.Is(err, sql.ErrNoRows))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInventoryRepo_ReconcileStock(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	inventoryRepo := NewInventoryRepository(sqlxDB)
+
+	t.Run("ReconcileStock", func(t *testing.T) {
+		itemID := uuid.New()
+		rows := sqlmock.NewRows([]string{"item_id", "warehouse_id", "stock_qty", "ledger_qty"}).
+			AddRow(itemID, models.DefaultWarehouseID, 7, 5)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockStock).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(getStockDrifts).WillReturnRows(rows)
+		mock.ExpectExec(reconcileItem).WithArgs(itemID, models.DefaultWarehouseID, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		drifts, err := inventoryRepo.ReconcileStock(context.Background())
+		require.NoError(t, err)
+		require.Len(t, drifts, 1)
+		require.Equal(t, 7, drifts[0].StockQty)
+		require.Equal(t, 5, drifts[0].LedgerQty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -13,8 +13,8 @@ import (
 )
 
 const (
-	basePrefix    = "api-inventory"
-	cacheDuration = 3600
+	basePrefix   = "api-inventory"
+	stockChannel = "api-inventory-stock"
 )
 
 func NewInventoryRedisRepo(redisClient *redis.Client) inventory.RedisRepository {
@@ -68,6 +68,64 @@ func (i *inventoryRedisRepo) SetItemCtx(ctx context.Context, key string, seconds
 	return nil
 }
 
+// Notify subscribers about item quantity changes
+func (i *inventoryRedisRepo) PublishItemsCtx(ctx context.Context, items []*models.InventoryItem) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRedisRepo.PublishItemsCtx")
+	defer span.Finish()
+
+	for _, item := range items {
+		itemBytes, err := json.Marshal(item)
+		if err != nil {
+			return errors.Wrap(err, "inventoryRedisRepo.PublishItemsCtx.json.Marshal")
+		}
+		if err = i.redisClient.Publish(ctx, stockChannel, itemBytes).Err(); err != nil {
+			return errors.Wrap(err, "inventoryRedisRepo.PublishItemsCtx.redisClient.Publish")
+		}
+	}
+	return nil
+}
+
+// Receive item quantity changes until context is done, channel is closed after that
+func (i *inventoryRedisRepo) SubscribeItemsCtx(ctx context.Context) (<-chan *models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryRedisRepo.SubscribeItemsCtx")
+	defer span.Finish()
+
+	pubsub := i.redisClient.Subscribe(ctx, stockChannel)
+	// Wait for confirmation so no change published after return is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, errors.Wrap(err, "inventoryRedisRepo.SubscribeItemsCtx.pubsub.Receive")
+	}
+
+	items := make(chan *models.InventoryItem)
+	go func() {
+		defer close(items)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				item := &models.InventoryItem{}
+				if err := json.Unmarshal([]byte(msg.Payload), item); err != nil {
+					continue
+				}
+				select {
+				case items <- item:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return items, nil
+}
+
 func (i *inventoryRedisRepo) createKey(itemID string) string {
 	return fmt.Sprintf("%s: %s", basePrefix, itemID)
 }
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+func SetupRedis() inventory.RedisRepository {
+	mr, err := miniredis.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	inventoryRedisRepo := NewInventoryRedisRepo(client)
+	return inventoryRedisRepo
+}
+
+func TestInventoryRedisRepo_SetItemCtx(t *testing.T) {
+	t.Parallel()
+
+	inventoryRedisRepo := SetupRedis()
+
+	t.Run("SetItemCtx", func(t *testing.T) {
+		item := &models.InventoryItem{UUID: uuid.New(), Qty: 3}
+		require.NoError(t, inventoryRedisRepo.SetItemCtx(context.Background(), item.UUID.String(), 10, item))
+
+		cached, err := inventoryRedisRepo.GetByIDCtx(context.Background(), item.UUID.String())
+		require.NoError(t, err)
+		require.Equal(t, item, cached)
+
+		require.NoError(t, inventoryRedisRepo.DeleteItemCtx(context.Background(), item.UUID.String()))
+
+		cached, err = inventoryRedisRepo.GetByIDCtx(context.Background(), item.UUID.String())
+		require.NoError(t, err)
+		require.Nil(t, cached)
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+const (
+	getItemByID   = `SELECT item_id, SUM(qty) AS qty FROM inventory_items WHERE item_id = $1 GROUP BY item_id`
+	getItemStocks = `SELECT item_id, warehouse_id, qty FROM inventory_items
+						WHERE item_id IN (%s) AND qty > 0
+						ORDER BY item_id, warehouse_id`
+	getItemsCount = `SELECT COUNT(DISTINCT item_id) FROM inventory_items`
+	getItems      = `SELECT item_id, SUM(qty) AS qty FROM inventory_items
+						GROUP BY item_id
+						ORDER BY item_id OFFSET $1 LIMIT $2`
+	getAllStocks = `SELECT item_id, warehouse_id, qty FROM inventory_items
+						WHERE qty > 0
+						ORDER BY item_id, warehouse_id`
+	addItem = `INSERT INTO inventory_items (item_id, warehouse_id, qty, created_at)
+						VALUES ($1, $2, $3, now())
+						ON CONFLICT (item_id, warehouse_id) DO UPDATE
+							SET qty = inventory_items.qty + EXCLUDED.qty,
+								updated_at = now()
+						RETURNING item_id, warehouse_id, qty`
+	getItemStocksForUpdate = `SELECT item_id, warehouse_id, qty FROM inventory_items
+						WHERE item_id = $1
+						ORDER BY warehouse_id FOR UPDATE`
+	removeItem = `UPDATE inventory_items
+						SET qty = qty - $1,
+							updated_at = now()
+						WHERE item_id = $2 AND warehouse_id = $3
+						RETURNING item_id, warehouse_id, qty`
+
+	createReservation = `INSERT INTO inventory_reservations (reservation_id, order_id, expires_at, created_at)
+						VALUES ($1, $2, $3, now())`
+	createReservationItem = `INSERT INTO inventory_reservation_items (reservation_id, item_id, qty)
+						VALUES ($1, $2, $3)`
+	getReservation = `SELECT reservation_id, order_id, expires_at FROM inventory_reservations
+						WHERE reservation_id = $1 AND expires_at > now()`
+	getReservationItems = `SELECT item_id, qty FROM inventory_reservation_items
+						WHERE reservation_id = $1
+						ORDER BY item_id`
+	getReservationForUpdate = `SELECT reservation_id FROM inventory_reservations
+						WHERE reservation_id = $1 AND expires_at > now() FOR UPDATE`
+	extendReservation = `UPDATE inventory_reservations
+						SET expires_at = $2
+						WHERE reservation_id = $1 AND expires_at > now()`
+	deleteReservation         = `DELETE FROM inventory_reservations WHERE reservation_id = $1`
+	deleteExpiredReservations = `DELETE FROM inventory_reservations WHERE expires_at <= now()`
+	getReservedQty            = `SELECT COALESCE(SUM(ri.qty), 0) FROM inventory_reservation_items ri
+							JOIN inventory_reservations r ON r.reservation_id = ri.reservation_id
+						WHERE ri.item_id = $1 AND r.expires_at > now()`
+
+	createWarehouse = `INSERT INTO warehouses (warehouse_id, name, address, latitude, longitude, created_at)
+						VALUES ($1, $2, $3, $4, $5, now())
+						RETURNING *`
+	updateWarehouse = `UPDATE warehouses
+						SET name = COALESCE(NULLIF($1, ''), name),
+							address = $2,
+							latitude = $3,
+							longitude = $4,
+							updated_at = now()
+						WHERE warehouse_id = $5
+						RETURNING *`
+	getWarehouseByID = `SELECT warehouse_id, name, address, latitude, longitude, created_at, updated_at
+						FROM warehouses WHERE warehouse_id = $1`
+	getWarehouses = `SELECT warehouse_id, name, address, latitude, longitude, created_at, updated_at
+						FROM warehouses ORDER BY name, warehouse_id`
+	// Warehouse is kept while it holds any stock
+	deleteWarehouse = `DELETE FROM warehouses WHERE warehouse_id = $1
+						AND NOT EXISTS (SELECT 1 FROM inventory_items WHERE warehouse_id = $1 AND qty > 0)`
+
+	createMovement = `INSERT INTO inventory_movements (item_id, warehouse_id, delta, reason, order_id, actor, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, now())`
+	getMovementsCount = `SELECT COUNT(movement_id) FROM inventory_movements`
+	getMovements      = `SELECT movement_id, item_id, warehouse_id, delta, reason, order_id, actor, created_at
+						FROM inventory_movements`
+	// Stock rebuilt from ledger compared with stored stock of existing warehouses
+	getStockDrifts = `SELECT COALESCE(s.item_id, l.item_id)           AS item_id,
+							COALESCE(s.warehouse_id, l.warehouse_id) AS warehouse_id,
+							COALESCE(s.qty, 0)                       AS stock_qty,
+							COALESCE(l.qty, 0)                       AS ledger_qty
+						FROM inventory_items s
+							FULL OUTER JOIN (SELECT item_id, warehouse_id, SUM(delta) AS qty
+											FROM inventory_movements
+											GROUP BY item_id, warehouse_id) l
+								ON s.item_id = l.item_id AND s.warehouse_id = l.warehouse_id
+						WHERE COALESCE(s.qty, 0) <> COALESCE(l.qty, 0)
+							AND EXISTS (SELECT 1 FROM warehouses w
+										WHERE w.warehouse_id = COALESCE(s.warehouse_id, l.warehouse_id))
+						ORDER BY 1, 2`
+	lockStock     = `LOCK TABLE inventory_items IN SHARE ROW EXCLUSIVE MODE`
+	reconcileItem = `INSERT INTO inventory_items (item_id, warehouse_id, qty, created_at)
+						VALUES ($1, $2, $3, now())
+						ON CONFLICT (item_id, warehouse_id) DO UPDATE
+							SET qty = EXCLUDED.qty,
+								updated_at = now()`
+
+	getThresholds = `SELECT item_id, threshold FROM inventory_thresholds WHERE item_id IN (%s)`
+	setThreshold  = `INSERT INTO inventory_thresholds (item_id, threshold, created_at)
+						VALUES ($1, $2, now())
+						ON CONFLICT (item_id) DO UPDATE
+							SET threshold = EXCLUDED.threshold,
+								updated_at = now()
+						RETURNING item_id, threshold`
+)
@@ -4,11 +4,35 @@ package inventory
 import (
 	"context"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	"github.com/google/uuid"
+	"time"
 )
 
 type UseCase interface {
-	AddItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error)
+	AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error)
+	ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error)
+	ExportItems(ctx context.Context) ([]*models.InventoryItem, error)
+	GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error)
+	GetItemDetails(ctx context.Context, itemID uuid.UUID) (*models.InventoryItemDetails, error)
+	AdjustStock(ctx context.Context, itemID uuid.UUID, adjustment *models.StockAdjustment, actor string) (*models.InventoryItemDetails, error)
 	GetItemByID(ctx context.Context, item uuid.UUID) (*models.InventoryItem, error)
-	RemoveItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error)
+	GetItemStocks(ctx context.Context, item uuid.UUID) ([]*models.InventoryItem, error)
+	GetAvailableItem(ctx context.Context, item uuid.UUID) (*models.InventoryItem, error)
+	RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, []*models.InventoryItem, error)
+	AllocateItems(ctx context.Context, items []*models.InventoryItem, options models.AllocationOptions) ([]*models.InventoryItem, []*models.InventoryItem, error)
+	Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error)
+	CommitReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID, actor string, options models.AllocationOptions) error
+	ExtendReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID, ttl time.Duration) (*models.InventoryReservation, error)
+	ReleaseReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID) error
+	ListMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error)
+	ReconcileStock(ctx context.Context, apply bool) ([]*models.StockDrift, error)
+	WatchItems(ctx context.Context, itemIDs []uuid.UUID) (<-chan *models.InventoryItem, error)
+	GetThreshold(ctx context.Context, itemID uuid.UUID) (*models.ItemThreshold, error)
+	SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error)
+	CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error)
+	GetWarehouses(ctx context.Context) ([]*models.Warehouse, error)
+	DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error
 }
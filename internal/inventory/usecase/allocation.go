@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"bytes"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/google/uuid"
+	"math"
+	"sort"
+)
+
+// Split requested items over warehouse stocks in strategy order.
+// Returns per warehouse allocations, or short items with quantity in stock when some item can not be allocated.
+func allocateItems(items []*models.InventoryItem, stocks []*models.InventoryItem, warehouses []*models.Warehouse, options models.AllocationOptions) ([]*models.InventoryItem, []*models.InventoryItem) {
+	stocksByItem := make(map[uuid.UUID][]*models.InventoryItem, len(items))
+	for _, stock := range stocks {
+		stocksByItem[stock.UUID] = append(stocksByItem[stock.UUID], stock)
+	}
+	distances := warehouseDistances(warehouses, options.Location)
+
+	allocations := make([]*models.InventoryItem, 0, len(items))
+	shortItems := make([]*models.InventoryItem, 0)
+	for _, item := range items {
+		itemStocks := stocksByItem[item.UUID]
+		sortStocks(itemStocks, options.Strategy, distances)
+
+		left := item.Qty
+		inStock := 0
+		for _, stock := range itemStocks {
+			inStock += stock.Qty
+			if left == 0 {
+				continue
+			}
+			qty := stock.Qty
+			if qty > left {
+				qty = left
+			}
+			allocations = append(allocations, &models.InventoryItem{UUID: item.UUID, WarehouseID: stock.WarehouseID, Qty: qty})
+			left -= qty
+		}
+		if left > 0 {
+			shortItems = append(shortItems, &models.InventoryItem{UUID: item.UUID, Qty: inStock})
+		}
+	}
+
+	if len(shortItems) > 0 {
+		return nil, shortItems
+	}
+	return allocations, nil
+}
+
+// Distance from location to every warehouse, nil without location
+func warehouseDistances(warehouses []*models.Warehouse, location *models.Location) map[uuid.UUID]float64 {
+	if location == nil {
+		return nil
+	}
+	distances := make(map[uuid.UUID]float64, len(warehouses))
+	for _, w := range warehouses {
+		distances[w.WarehouseID] = location.DistanceTo(models.Location{Latitude: w.Latitude, Longitude: w.Longitude})
+	}
+	return distances
+}
+
+// Order stocks to take from, nearest strategy without distances falls back to most stock
+func sortStocks(stocks []*models.InventoryItem, strategy models.AllocationStrategy, distances map[uuid.UUID]float64) {
+	distance := func(warehouseID uuid.UUID) float64 {
+		if d, ok := distances[warehouseID]; ok {
+			return d
+		}
+		return math.Inf(1)
+	}
+	sort.SliceStable(stocks, func(i, j int) bool {
+		if strategy == models.AllocationStrategyNearest && distances != nil {
+			if di, dj := distance(stocks[i].WarehouseID), distance(stocks[j].WarehouseID); di != dj {
+				return di < dj
+			}
+		}
+		if stocks[i].Qty != stocks[j].Qty {
+			return stocks[i].Qty > stocks[j].Qty
+		}
+		return bytes.Compare(stocks[i].WarehouseID[:], stocks[j].WarehouseID[:]) < 0
+	})
+}
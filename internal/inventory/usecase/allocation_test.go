@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+func TestAllocateItems(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New()
+	near := &models.Warehouse{WarehouseID: uuid.New(), Latitude: 55.75, Longitude: 37.61}
+	far := &models.Warehouse{WarehouseID: uuid.New(), Latitude: 59.93, Longitude: 30.33}
+	warehouses := []*models.Warehouse{near, far}
+	stocks := func() []*models.InventoryItem {
+		return []*models.InventoryItem{
+			{UUID: itemID, WarehouseID: near.WarehouseID, Qty: 2},
+			{UUID: itemID, WarehouseID: far.WarehouseID, Qty: 10},
+		}
+	}
+
+	t.Run("MostStock", func(t *testing.T) {
+		allocations, shortItems := allocateItems(
+			[]*models.InventoryItem{{UUID: itemID, Qty: 4}},
+			stocks(),
+			warehouses,
+			models.AllocationOptions{Strategy: models.AllocationStrategyMostStock},
+		)
+		require.Empty(t, shortItems)
+		require.Equal(t, []*models.InventoryItem{{UUID: itemID, WarehouseID: far.WarehouseID, Qty: 4}}, allocations)
+	})
+
+	t.Run("Nearest", func(t *testing.T) {
+		allocations, shortItems := allocateItems(
+			[]*models.InventoryItem{{UUID: itemID, Qty: 4}},
+			stocks(),
+			warehouses,
+			models.AllocationOptions{
+				Strategy: models.AllocationStrategyNearest,
+				Location: &models.Location{Latitude: 55.7, Longitude: 37.6},
+			},
+		)
+		require.Empty(t, shortItems)
+		require.Equal(t, []*models.InventoryItem{
+			{UUID: itemID, WarehouseID: near.WarehouseID, Qty: 2},
+			{UUID: itemID, WarehouseID: far.WarehouseID, Qty: 2},
+		}, allocations)
+	})
+
+	t.Run("NotEnough", func(t *testing.T) {
+		allocations, shortItems := allocateItems(
+			[]*models.InventoryItem{{UUID: itemID, Qty: 13}},
+			stocks(),
+			warehouses,
+			models.AllocationOptions{Strategy: models.AllocationStrategyMostStock},
+		)
+		require.Empty(t, allocations)
+		require.Equal(t, []*models.InventoryItem{{UUID: itemID, Qty: 12}}, shortItems)
+	})
+}
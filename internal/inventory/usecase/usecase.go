@@ -2,68 +2,773 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"github.com/engineerXIII/maiSystemBackend/config"
 	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/product"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// Reservation lifetime when caller does not set expiration
+	reservationTTL = 15 * time.Minute
+	cacheDuration  = 3600
 )
 
 type inventoryUC struct {
-	cfg       *config.Config
-	redisRepo inventory.RedisRepository
-	logger    logger.Logger
+	cfg           *config.Config
+	inventoryRepo inventory.Repository
+	redisRepo     inventory.RedisRepository
+	publisher     inventory.Publisher
+	productClient product.Client
+	logger        logger.Logger
 }
 
-func NewInventoryUseCase(cfg *config.Config, redisRepo inventory.RedisRepository, log logger.Logger) inventory.UseCase {
-	return &inventoryUC{cfg: cfg, redisRepo: redisRepo, logger: log}
+func NewInventoryUseCase(cfg *config.Config, inventoryRepo inventory.Repository, redisRepo inventory.RedisRepository, publisher inventory.Publisher, productClient product.Client, log logger.Logger) inventory.UseCase {
+	return &inventoryUC{cfg: cfg, inventoryRepo: inventoryRepo, redisRepo: redisRepo, publisher: publisher, productClient: productClient, logger: log}
 }
 
-func (i *inventoryUC) AddItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.AddItem")
+// Add exact quantities of items, returns resulting items
+func (i *inventoryUC) AddItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.AddItems")
 	defer span.Finish()
 
-	inventoryItem, err := i.redisRepo.GetByIDCtx(ctx, item.UUID.String())
+	stocks := mergeItems(items)
+	for _, stock := range stocks {
+		if stock.WarehouseID == uuid.Nil {
+			stock.WarehouseID = models.DefaultWarehouseID
+		}
+	}
+
+	resultItems, err := i.inventoryRepo.AddItems(ctx, mergeItems(stocks), change)
 	if err != nil {
 		return nil, err
 	}
-	if inventoryItem != nil {
-		inventoryItem.Qty = inventoryItem.Qty + 1
-	} else {
-		inventoryItem = &models.InventoryItem{
-			UUID: item.UUID,
-			Qty:  item.Qty,
+	i.invalidateItems(ctx, resultItems)
+	i.publishAvailability(ctx, resultItems)
+	return resultItems, nil
+}
+
+// Add stock of valid rows, rows with unknown products, unknown warehouses or empty quantity are reported and skipped
+func (i *inventoryUC) ImportItems(ctx context.Context, rows []*models.InventoryImportRow, change models.StockChange) (*models.InventoryImportResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ImportItems")
+	defer span.Finish()
+
+	warehouses, err := i.inventoryRepo.GetWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	knownWarehouses := make(map[uuid.UUID]bool, len(warehouses))
+	for _, warehouse := range warehouses {
+		knownWarehouses[warehouse.WarehouseID] = true
+	}
+
+	result := &models.InventoryImportResult{Errors: make([]*models.InventoryImportError, 0)}
+	knownProducts := make(map[uuid.UUID]bool)
+	items := make([]*models.InventoryItem, 0, len(rows))
+	for _, row := range rows {
+		item := row.Item
+		if item.Qty <= 0 {
+			result.Errors = append(result.Errors, &models.InventoryImportError{Line: row.Line, Message: "empty quantity"})
+			continue
+		}
+		if item.WarehouseID == uuid.Nil {
+			item.WarehouseID = models.DefaultWarehouseID
+		}
+		if !knownWarehouses[item.WarehouseID] {
+			result.Errors = append(result.Errors, &models.InventoryImportError{Line: row.Line, Message: "unknown warehouse " + item.WarehouseID.String()})
+			continue
+		}
+
+		known, ok := knownProducts[item.UUID]
+		if !ok {
+			if _, err = i.productClient.GetProductByID(ctx, item.UUID); err != nil && !errors.Is(err, httpErrors.NotFound) {
+				return nil, errors.Wrap(err, "inventoryUC.ImportItems.GetProductByID")
+			}
+			known = err == nil
+			knownProducts[item.UUID] = known
+		}
+		if !known {
+			result.Errors = append(result.Errors, &models.InventoryImportError{Line: row.Line, Message: "unknown product " + item.UUID.String()})
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	if len(items) > 0 {
+		if _, err = i.AddItems(ctx, items, change); err != nil {
+			return nil, err
+		}
+	}
+	result.Imported = len(items)
+	return result, nil
+}
+
+// Get stocks of all items per warehouse
+func (i *inventoryUC) ExportItems(ctx context.Context) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ExportItems")
+	defer span.Finish()
+
+	return i.inventoryRepo.GetAllStocks(ctx)
+}
+
+// Get page of item stocks with available quantities
+func (i *inventoryUC) GetItems(ctx context.Context, pq *utils.PaginationQuery) (*models.InventoryItemList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetItems")
+	defer span.Finish()
+
+	itemList, err := i.inventoryRepo.GetItems(ctx, pq)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range itemList.Items {
+		if item.Available, err = i.getAvailableQty(ctx, item.ItemID); err != nil {
+			return nil, err
 		}
 	}
-	return nil, i.redisRepo.SetItemCtx(ctx, inventoryItem.UUID.String(), 3600, inventoryItem)
+	return itemList, nil
 }
 
+// Get item stock with available quantity and stock per warehouse, unknown item has no stock
+func (i *inventoryUC) GetItemDetails(ctx context.Context, itemID uuid.UUID) (*models.InventoryItemDetails, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetItemDetails")
+	defer span.Finish()
+
+	stocks, err := i.inventoryRepo.GetItemStocks(ctx, []uuid.UUID{itemID})
+	if err != nil {
+		return nil, err
+	}
+	available, err := i.getAvailableQty(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &models.InventoryItemDetails{ItemID: itemID, Available: available, Warehouses: make([]*models.WarehouseStock, 0, len(stocks))}
+	for _, stock := range stocks {
+		details.Qty += stock.Qty
+		details.Warehouses = append(details.Warehouses, &models.WarehouseStock{WarehouseID: stock.WarehouseID, Qty: stock.Qty})
+	}
+	return details, nil
+}
+
+// Correct item stock in warehouse, default warehouse when unset, fails when warehouse holds less than taken out
+func (i *inventoryUC) AdjustStock(ctx context.Context, itemID uuid.UUID, adjustment *models.StockAdjustment, actor string) (*models.InventoryItemDetails, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.AdjustStock")
+	defer span.Finish()
+
+	if err := utils.ValidateStruct(ctx, adjustment); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "inventoryUC.AdjustStock.ValidateStruct"))
+	}
+
+	change := models.StockChange{Reason: adjustment.Reason, Actor: actor}
+	if change.Reason == "" {
+		change.Reason = models.InventoryMovementReasonAdjustment
+	}
+	warehouseID := adjustment.WarehouseID
+	if warehouseID == uuid.Nil {
+		warehouseID = models.DefaultWarehouseID
+	}
+
+	if adjustment.Delta > 0 {
+		items := []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouseID, Qty: adjustment.Delta}}
+		if _, err := i.AddItems(ctx, items, change); err != nil {
+			return nil, err
+		}
+		return i.GetItemDetails(ctx, itemID)
+	}
+
+	items := []*models.InventoryItem{{UUID: itemID, WarehouseID: warehouseID, Qty: -adjustment.Delta}}
+	_, shortItems, err := i.RemoveItems(ctx, items, change)
+	if err != nil {
+		return nil, err
+	}
+	if len(shortItems) > 0 {
+		return nil, httpErrors.NewRestErrorWithMessage(
+			http.StatusConflict,
+			fmt.Sprintf("%s: %s (requested %d, available %d)", httpErrors.NotEnoughStock.Error(), itemID, -adjustment.Delta, shortItems[0].Qty),
+			shortItems,
+		)
+	}
+	return i.GetItemDetails(ctx, itemID)
+}
+
+// Get stock item from cache, falling back to storage
 func (i *inventoryUC) GetItemByID(c context.Context, item uuid.UUID) (*models.InventoryItem, error) {
 	span, ctx := opentracing.StartSpanFromContext(c, "inventoryUC.GetItemByID")
 	defer span.Finish()
-	inventoryItem, err := i.redisRepo.GetByIDCtx(ctx, item.String())
+
+	cachedItem, err := i.redisRepo.GetByIDCtx(ctx, item.String())
+	if err != nil {
+		i.logger.Errorf("inventoryUC.GetItemByID.GetByIDCtx: %s", err)
+	}
+	if cachedItem != nil {
+		return cachedItem, nil
+	}
+
+	inventoryItem, err := i.inventoryRepo.GetItemByID(ctx, item)
 	if err != nil {
 		return nil, err
 	}
-	if inventoryItem != nil {
-		return inventoryItem, nil
-	} else {
+	if inventoryItem == nil {
 		return nil, nil
 	}
+
+	if err = i.redisRepo.SetItemCtx(ctx, item.String(), cacheDuration, inventoryItem); err != nil {
+		i.logger.Errorf("inventoryUC.GetItemByID.SetItemCtx: %s", err)
+	}
+	return inventoryItem, nil
+}
+
+// Get item stock in every warehouse
+func (i *inventoryUC) GetItemStocks(ctx context.Context, item uuid.UUID) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetItemStocks")
+	defer span.Finish()
+
+	return i.inventoryRepo.GetItemStocks(ctx, []uuid.UUID{item})
+}
+
+// Remove exact quantities of all items at once, returns resulting items
+// or short items with available quantity when nothing was removed.
+// Items without warehouse are taken from warehouses with most stock.
+func (i *inventoryUC) RemoveItems(ctx context.Context, items []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.RemoveItems")
+	defer span.Finish()
+
+	stocks := make([]*models.InventoryItem, 0, len(items))
+	unallocated := make([]*models.InventoryItem, 0)
+	for _, item := range mergeItems(items) {
+		if item.WarehouseID == uuid.Nil {
+			unallocated = append(unallocated, item)
+			continue
+		}
+		stocks = append(stocks, item)
+	}
+	if len(unallocated) > 0 {
+		allocations, shortItems, err := i.allocate(ctx, unallocated, models.AllocationOptions{Strategy: models.AllocationStrategyMostStock})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(shortItems) > 0 {
+			return nil, shortItems, nil
+		}
+		stocks = mergeItems(append(stocks, allocations...))
+	}
+
+	resultItems, shortItems, err := i.removeStocks(ctx, stocks, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	i.publishAvailability(ctx, resultItems)
+	return resultItems, shortItems, nil
+}
+
+// Plan which warehouses give items not held by active reservations,
+// returns per warehouse allocations or short items with available quantity
+func (i *inventoryUC) AllocateItems(ctx context.Context, items []*models.InventoryItem, options models.AllocationOptions) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.AllocateItems")
+	defer span.Finish()
+
+	merged := mergeItems(items)
+	shortItems := make([]*models.InventoryItem, 0)
+	for _, item := range merged {
+		available, err := i.getAvailableQty(ctx, item.UUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if available < item.Qty {
+			shortItems = append(shortItems, &models.InventoryItem{UUID: item.UUID, Qty: available})
+		}
+	}
+	if len(shortItems) > 0 {
+		return nil, shortItems, nil
+	}
+
+	return i.allocate(ctx, merged, options)
+}
+
+// Remove exact per warehouse quantities
+func (i *inventoryUC) removeStocks(ctx context.Context, stocks []*models.InventoryItem, change models.StockChange) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	removal, err := i.inventoryRepo.RemoveItems(ctx, stocks, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	i.stocksRemoved(ctx, removal)
+	return removal.Items, removal.Short, nil
+}
+
+// Drop cached items and alert about low stock after removal
+func (i *inventoryUC) stocksRemoved(ctx context.Context, removal *models.StockRemoval) {
+	i.invalidateItems(ctx, removal.Items)
+	if len(removal.Short) == 0 {
+		i.alertLowStock(ctx, removal.Levels)
+	}
+}
+
+// Hold requested quantities, returns items which are short with available quantity
+func (i *inventoryUC) Reserve(ctx context.Context, reservation *models.InventoryReservation) ([]*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.Reserve")
+	defer span.Finish()
+
+	if reservation.ReservationID == uuid.Nil {
+		reservation.ReservationID = uuid.New()
+	}
+	if reservation.ExpiresAt.IsZero() {
+		reservation.ExpiresAt = time.Now().Add(reservationTTL)
+	}
+
+	if !reservation.ExpiresAt.After(time.Now()) {
+		return nil, httpErrors.NewBadRequestError("reservation already expired")
+	}
+	reservation.Items = mergeItems(reservation.Items)
+
+	shortItems, err := i.inventoryRepo.CreateReservation(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+	if len(shortItems) > 0 {
+		return shortItems, nil
+	}
+	i.publishAvailability(ctx, reservation.Items)
+	return nil, nil
+}
+
+// Get item with quantity not held by active reservations
+func (i *inventoryUC) GetAvailableItem(ctx context.Context, item uuid.UUID) (*models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetAvailableItem")
+	defer span.Finish()
+
+	inventoryItem, err := i.GetItemByID(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if inventoryItem == nil {
+		return nil, nil
+	}
+
+	available, err := i.getAvailableQty(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	return &models.InventoryItem{UUID: inventoryItem.UUID, Qty: available}, nil
+}
+
+// Take reserved quantities out of warehouses chosen by allocation options and drop the reservation at once
+func (i *inventoryUC) CommitReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID, actor string, options models.AllocationOptions) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.CommitReservation")
+	defer span.Finish()
+
+	reservation, err := i.getReservation(ctx, reservationID, orderID)
+	if err != nil {
+		return err
+	}
+
+	allocations, shortItems, err := i.allocate(ctx, mergeItems(reservation.Items), options)
+	if err != nil {
+		return err
+	}
+	if len(shortItems) == 0 {
+		var removal *models.StockRemoval
+		removal, err = i.inventoryRepo.CommitReservation(ctx, reservationID, allocations, models.StockChange{
+			Reason:  models.InventoryMovementReasonOrder,
+			OrderID: &orderID,
+			Actor:   actor,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(httpErrors.NotFound, "reservation %s", reservationID)
+			}
+			return err
+		}
+		i.stocksRemoved(ctx, removal)
+		shortItems = removal.Short
+	}
+	if len(shortItems) > 0 {
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusConflict,
+			fmt.Sprintf("%s: reservation %s", httpErrors.NotEnoughStock.Error(), reservationID),
+			shortItems,
+		)
+	}
+
+	i.publishAvailability(ctx, reservation.Items)
+	return nil
+}
+
+// Hold reserved quantities for ttl from now, expired reservation can not be extended
+func (i *inventoryUC) ExtendReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID, ttl time.Duration) (*models.InventoryReservation, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ExtendReservation")
+	defer span.Finish()
+
+	if ttl <= 0 {
+		ttl = reservationTTL
+	}
+	reservation, err := i.getReservation(ctx, reservationID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	if err = i.inventoryRepo.ExtendReservation(ctx, reservationID, expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.NotFound, "reservation %s", reservationID)
+		}
+		return nil, err
+	}
+	reservation.ExpiresAt = expiresAt
+	return reservation, nil
 }
 
-func (i *inventoryUC) RemoveItem(ctx context.Context, item *models.InventoryItem) (*models.InventoryItem, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.RemoveItem")
+// Return reserved quantities back to available stock
+func (i *inventoryUC) ReleaseReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ReleaseReservation")
 	defer span.Finish()
 
-	inventoryItem, err := i.redisRepo.GetByIDCtx(ctx, item.UUID.String())
+	reservation, err := i.getReservation(ctx, reservationID, orderID)
+	if err != nil {
+		return err
+	}
+
+	if err = i.inventoryRepo.DeleteReservation(ctx, reservationID); err != nil {
+		return err
+	}
+	i.publishAvailability(ctx, reservation.Items)
+	return nil
+}
+
+// Get stock ledger entries
+func (i *inventoryUC) ListMovements(ctx context.Context, filter *models.InventoryMovementFilter, pq *utils.PaginationQuery) (*models.InventoryMovementList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ListMovements")
+	defer span.Finish()
+
+	return i.inventoryRepo.GetMovements(ctx, filter, pq)
+}
+
+// Find stock drifted from ledger, fix stored stock when apply is set
+func (i *inventoryUC) ReconcileStock(ctx context.Context, apply bool) ([]*models.StockDrift, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.ReconcileStock")
+	defer span.Finish()
+
+	if !apply {
+		return i.inventoryRepo.GetStockDrifts(ctx)
+	}
+
+	drifts, err := i.inventoryRepo.ReconcileStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*models.InventoryItem, 0, len(drifts))
+	for _, drift := range drifts {
+		items = append(items, &models.InventoryItem{UUID: drift.ItemID})
+		i.logger.Infof("inventoryUC.ReconcileStock: item %s in warehouse %s fixed from %d to %d",
+			drift.ItemID, drift.WarehouseID, drift.StockQty, drift.LedgerQty)
+		if err = i.redisRepo.DeleteItemCtx(ctx, drift.ItemID.String()); err != nil {
+			i.logger.Errorf("inventoryUC.ReconcileStock.DeleteItemCtx: %s", err)
+		}
+	}
+	i.publishAvailability(ctx, items)
+	return drifts, nil
+}
+
+// Get available quantity changes of items, all items when none is set.
+// Channel is closed when context is done.
+func (i *inventoryUC) WatchItems(ctx context.Context, itemIDs []uuid.UUID) (<-chan *models.InventoryItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.WatchItems")
+	defer span.Finish()
+
+	changes, err := i.redisRepo.SubscribeItemsCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
-	inventoryItem.Qty = inventoryItem.Qty - 1
-	if inventoryItem.Qty == 0 {
-		return nil, i.redisRepo.DeleteItemCtx(ctx, item.UUID.String())
+	if len(itemIDs) == 0 {
+		return changes, nil
+	}
+
+	watched := make(map[uuid.UUID]struct{}, len(itemIDs))
+	for _, itemID := range itemIDs {
+		watched[itemID] = struct{}{}
+	}
+	filtered := make(chan *models.InventoryItem)
+	go func() {
+		defer close(filtered)
+		for item := range changes {
+			if _, ok := watched[item.UUID]; !ok {
+				continue
+			}
+			select {
+			case filtered <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return filtered, nil
+}
+
+// Get item threshold, default threshold when item has no own one
+func (i *inventoryUC) GetThreshold(ctx context.Context, itemID uuid.UUID) (*models.ItemThreshold, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetThreshold")
+	defer span.Finish()
+
+	thresholds, err := i.getThresholds(ctx, []uuid.UUID{itemID})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ItemThreshold{ItemID: itemID, Threshold: thresholds[itemID]}, nil
+}
+
+// Set item threshold
+func (i *inventoryUC) SetThreshold(ctx context.Context, threshold *models.ItemThreshold) (*models.ItemThreshold, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.SetThreshold")
+	defer span.Finish()
+
+	if threshold.Threshold < 0 {
+		return nil, httpErrors.NewBadRequestError("negative threshold")
+	}
+	return i.inventoryRepo.SetThreshold(ctx, threshold)
+}
+
+// Create warehouse
+func (i *inventoryUC) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.CreateWarehouse")
+	defer span.Finish()
+
+	if err := utils.ValidateStruct(ctx, warehouse); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "inventoryUC.CreateWarehouse.ValidateStruct"))
+	}
+	if warehouse.WarehouseID == uuid.Nil {
+		warehouse.WarehouseID = uuid.New()
+	}
+
+	return i.inventoryRepo.CreateWarehouse(ctx, warehouse)
+}
+
+// Update warehouse
+func (i *inventoryUC) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.UpdateWarehouse")
+	defer span.Finish()
+
+	if err := utils.ValidateStruct(ctx, warehouse); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "inventoryUC.UpdateWarehouse.ValidateStruct"))
+	}
+	if _, err := i.GetWarehouseByID(ctx, warehouse.WarehouseID); err != nil {
+		return nil, err
+	}
+
+	return i.inventoryRepo.UpdateWarehouse(ctx, warehouse)
+}
+
+// Get warehouse by id
+func (i *inventoryUC) GetWarehouseByID(ctx context.Context, warehouseID uuid.UUID) (*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetWarehouseByID")
+	defer span.Finish()
+
+	warehouse, err := i.inventoryRepo.GetWarehouseByID(ctx, warehouseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(httpErrors.NotFound, "warehouse %s", warehouseID)
+		}
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// Get all warehouses
+func (i *inventoryUC) GetWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.GetWarehouses")
+	defer span.Finish()
+
+	return i.inventoryRepo.GetWarehouses(ctx)
+}
+
+// Delete warehouse, warehouse holding stock can not be deleted
+func (i *inventoryUC) DeleteWarehouse(ctx context.Context, warehouseID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "inventoryUC.DeleteWarehouse")
+	defer span.Finish()
+
+	if _, err := i.GetWarehouseByID(ctx, warehouseID); err != nil {
+		return err
+	}
+	if err := i.inventoryRepo.DeleteWarehouse(ctx, warehouseID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrapf(httpErrors.Conflict, "warehouse %s holds stock", warehouseID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Split items over warehouse stocks
+func (i *inventoryUC) allocate(ctx context.Context, items []*models.InventoryItem, options models.AllocationOptions) ([]*models.InventoryItem, []*models.InventoryItem, error) {
+	itemIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.UUID)
+	}
+	stocks, err := i.inventoryRepo.GetItemStocks(ctx, itemIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warehouses []*models.Warehouse
+	if options.Strategy == models.AllocationStrategyNearest && options.Location != nil {
+		if warehouses, err = i.inventoryRepo.GetWarehouses(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	allocations, shortItems := allocateItems(items, stocks, warehouses, options)
+	return allocations, shortItems, nil
+}
+
+// Get active reservation placed for the order
+func (i *inventoryUC) getReservation(ctx context.Context, reservationID uuid.UUID, orderID uuid.UUID) (*models.InventoryReservation, error) {
+	reservation, err := i.inventoryRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, errors.Wrapf(httpErrors.NotFound, "reservation %s", reservationID)
+	}
+	if reservation.OrderID != orderID {
+		return nil, errors.Wrapf(httpErrors.Conflict, "reservation %s belongs to another order", reservationID)
+	}
+	return reservation, nil
+}
+
+// Item quantity not held by active reservations
+func (i *inventoryUC) getAvailableQty(ctx context.Context, itemID uuid.UUID) (int, error) {
+	inventoryItem, err := i.GetItemByID(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	if inventoryItem == nil {
+		return 0, nil
+	}
+
+	reserved, err := i.inventoryRepo.GetReservedQty(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	if available := inventoryItem.Qty - reserved; available > 0 {
+		return available, nil
+	}
+	return 0, nil
+}
+
+// Drop cached items after stock change
+func (i *inventoryUC) invalidateItems(ctx context.Context, items []*models.InventoryItem) {
+	for _, item := range items {
+		if err := i.redisRepo.DeleteItemCtx(ctx, item.UUID.String()); err != nil {
+			i.logger.Errorf("inventoryUC.invalidateItems.DeleteItemCtx: %s", err)
+		}
+	}
+}
+
+// Publish alerts for items which stock crossed below threshold or ran out after removal
+func (i *inventoryUC) alertLowStock(ctx context.Context, levels []*models.StockLevel) {
+	itemIDs := make([]uuid.UUID, 0, len(levels))
+	for _, level := range levels {
+		itemIDs = append(itemIDs, level.ItemID)
+	}
+
+	thresholds, err := i.getThresholds(ctx, itemIDs)
+	if err != nil {
+		i.logger.Errorf("inventoryUC.alertLowStock.getThresholds: %s", err)
+		return
+	}
+
+	for _, level := range levels {
+		event := lowStockEvent(level.ItemID, level.Before, level.After, thresholds[level.ItemID])
+		if event == nil {
+			continue
+		}
+		if err = i.publisher.PublishLowStock(ctx, event); err != nil {
+			i.logger.Errorf("inventoryUC.alertLowStock.PublishLowStock: %s", err)
+		}
+	}
+}
+
+// Thresholds of items, default threshold for items without own one
+func (i *inventoryUC) getThresholds(ctx context.Context, itemIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	thresholds := make(map[uuid.UUID]int, len(itemIDs))
+	for _, itemID := range itemIDs {
+		thresholds[itemID] = i.cfg.LowStock.DefaultThreshold
+	}
+
+	own, err := i.inventoryRepo.GetThresholds(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, threshold := range own {
+		thresholds[threshold.ItemID] = threshold.Threshold
+	}
+	return thresholds, nil
+}
+
+// Notify watchers about available quantity of changed items
+func (i *inventoryUC) publishAvailability(ctx context.Context, items []*models.InventoryItem) {
+	published := make(map[uuid.UUID]struct{}, len(items))
+	available := make([]*models.InventoryItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := published[item.UUID]; ok {
+			continue
+		}
+		published[item.UUID] = struct{}{}
+
+		qty, err := i.getAvailableQty(ctx, item.UUID)
+		if err != nil {
+			i.logger.Errorf("inventoryUC.publishAvailability.getAvailableQty: %s", err)
+			continue
+		}
+		available = append(available, &models.InventoryItem{UUID: item.UUID, Qty: qty})
+	}
+	if len(available) == 0 {
+		return
+	}
+
+	if err := i.redisRepo.PublishItemsCtx(ctx, available); err != nil {
+		i.logger.Errorf("inventoryUC.publishAvailability.PublishItemsCtx: %s", err)
+	}
+}
+
+// Alert for stock change, nil when stock did not cross threshold or run out
+func lowStockEvent(itemID uuid.UUID, before int, after int, threshold int) *models.LowStockEvent {
+	event := &models.LowStockEvent{ItemID: itemID, Qty: after, Threshold: threshold, OccurredAt: time.Now().UTC()}
+	switch {
+	case after <= 0 && before > 0:
+		event.Kind = models.LowStockKindOutOfStock
+	case after < threshold && before >= threshold:
+		event.Kind = models.LowStockKindBelowThreshold
+	default:
+		return nil
+	}
+	return event
+}
+
+// Sum quantities of repeated items in the same warehouse
+func mergeItems(items []*models.InventoryItem) []*models.InventoryItem {
+	type stockKey struct {
+		item, warehouse uuid.UUID
+	}
+	merged := make([]*models.InventoryItem, 0, len(items))
+	byKey := make(map[stockKey]*models.InventoryItem, len(items))
+	for _, item := range items {
+		key := stockKey{item: item.UUID, warehouse: item.WarehouseID}
+		if found, ok := byKey[key]; ok {
+			found.Qty += item.Qty
+			continue
+		}
+		byKey[key] = &models.InventoryItem{UUID: item.UUID, WarehouseID: item.WarehouseID, Qty: item.Qty}
+		merged = append(merged, byKey[key])
 	}
-	return nil, i.redisRepo.SetItemCtx(ctx, inventoryItem.UUID.String(), 3600, inventoryItem)
+	return merged
 }
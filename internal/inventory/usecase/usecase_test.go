@@ -0,0 +1,435 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	productMock "github.com/engineerXIII/maiSystemBackend/internal/product/mock"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+)
+
+func TestInventoryUC_Reserve(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	itemID := uuid.New()
+
+	t.Run("NotEnoughAvailable", func(t *testing.T) {
+		reservation := &models.InventoryReservation{
+			OrderID: uuid.New(),
+			Items:   []*models.InventoryItem{{UUID: itemID, Qty: 2}, {UUID: itemID, Qty: 3}},
+		}
+		mockInventoryRepo.EXPECT().CreateReservation(gomock.Any(), reservation).
+			DoAndReturn(func(ctx context.Context, r *models.InventoryReservation) ([]*models.InventoryItem, error) {
+				require.Equal(t, []*models.InventoryItem{{UUID: itemID, Qty: 5}}, r.Items)
+				return []*models.InventoryItem{{UUID: itemID, Qty: 3}}, nil
+			})
+
+		shortItems, err := inventoryUC.Reserve(context.Background(), reservation)
+		require.NoError(t, err)
+		require.Len(t, shortItems, 1)
+		require.Equal(t, 3, shortItems[0].Qty)
+	})
+
+	t.Run("Reserve", func(t *testing.T) {
+		reservation := &models.InventoryReservation{
+			OrderID: uuid.New(),
+			Items:   []*models.InventoryItem{{UUID: itemID, Qty: 3}},
+		}
+		mockInventoryRepo.EXPECT().CreateReservation(gomock.Any(), reservation).Return(nil, nil)
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), itemID.String()).Return(&models.InventoryItem{UUID: itemID, Qty: 10}, nil)
+		mockInventoryRepo.EXPECT().GetReservedQty(gomock.Any(), itemID).Return(7, nil)
+		mockRedisRepo.EXPECT().PublishItemsCtx(gomock.Any(), []*models.InventoryItem{{UUID: itemID, Qty: 3}}).Return(nil)
+
+		shortItems, err := inventoryUC.Reserve(context.Background(), reservation)
+		require.NoError(t, err)
+		require.Empty(t, shortItems)
+		require.NotEqual(t, uuid.Nil, reservation.ReservationID)
+		require.False(t, reservation.ExpiresAt.IsZero())
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		reservation := &models.InventoryReservation{
+			OrderID:   uuid.New(),
+			Items:     []*models.InventoryItem{{UUID: itemID, Qty: 3}},
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}
+
+		_, err := inventoryUC.Reserve(context.Background(), reservation)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, httpErrors.ParseErrors(err).Status())
+	})
+}
+
+func TestInventoryUC_CommitReservation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	itemID := uuid.New()
+	reservation := &models.InventoryReservation{
+		ReservationID: uuid.New(),
+		OrderID:       uuid.New(),
+		Items:         []*models.InventoryItem{{UUID: itemID, Qty: 3}},
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	t.Run("CommitReservation", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetReservation(gomock.Any(), reservation.ReservationID).Return(reservation, nil)
+		smallWarehouse, largeWarehouse := uuid.New(), uuid.New()
+		mockInventoryRepo.EXPECT().GetItemStocks(gomock.Any(), []uuid.UUID{itemID}).Return([]*models.InventoryItem{
+			{UUID: itemID, WarehouseID: smallWarehouse, Qty: 2},
+			{UUID: itemID, WarehouseID: largeWarehouse, Qty: 5},
+		}, nil)
+		change := models.StockChange{Reason: models.InventoryMovementReasonOrder, OrderID: &reservation.OrderID, Actor: "scheduler"}
+		mockInventoryRepo.EXPECT().CommitReservation(gomock.Any(), reservation.ReservationID, []*models.InventoryItem{{UUID: itemID, WarehouseID: largeWarehouse, Qty: 3}}, change).
+			Return(&models.StockRemoval{
+				Items:  []*models.InventoryItem{{UUID: itemID, WarehouseID: largeWarehouse, Qty: 2}},
+				Levels: []*models.StockLevel{{ItemID: itemID, Before: 7, After: 4}},
+			}, nil)
+		mockRedisRepo.EXPECT().DeleteItemCtx(gomock.Any(), itemID.String()).Return(nil)
+		mockInventoryRepo.EXPECT().GetThresholds(gomock.Any(), []uuid.UUID{itemID}).Return(nil, nil)
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), itemID.String()).Return(&models.InventoryItem{UUID: itemID, Qty: 4}, nil)
+		mockInventoryRepo.EXPECT().GetReservedQty(gomock.Any(), itemID).Return(0, nil)
+		mockRedisRepo.EXPECT().PublishItemsCtx(gomock.Any(), []*models.InventoryItem{{UUID: itemID, Qty: 4}}).Return(nil)
+
+		err := inventoryUC.CommitReservation(context.Background(), reservation.ReservationID, reservation.OrderID, "scheduler", models.AllocationOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("ExpiredMeanwhile", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetReservation(gomock.Any(), reservation.ReservationID).Return(reservation, nil)
+		mockInventoryRepo.EXPECT().GetItemStocks(gomock.Any(), []uuid.UUID{itemID}).Return([]*models.InventoryItem{
+			{UUID: itemID, WarehouseID: models.DefaultWarehouseID, Qty: 5},
+		}, nil)
+		mockInventoryRepo.EXPECT().CommitReservation(gomock.Any(), reservation.ReservationID, gomock.Any(), gomock.Any()).
+			Return(nil, errors.Wrap(sql.ErrNoRows, "inventoryRepo.CommitReservation.GetContext"))
+
+		err := inventoryUC.CommitReservation(context.Background(), reservation.ReservationID, reservation.OrderID, "scheduler", models.AllocationOptions{})
+		require.True(t, errors.Is(err, httpErrors.NotFound))
+	})
+
+	t.Run("ExtendReservation", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetReservation(gomock.Any(), reservation.ReservationID).Return(reservation, nil)
+		mockInventoryRepo.EXPECT().ExtendReservation(gomock.Any(), reservation.ReservationID, gomock.Any()).Return(nil)
+
+		extended, err := inventoryUC.ExtendReservation(context.Background(), reservation.ReservationID, reservation.OrderID, time.Hour)
+		require.NoError(t, err)
+		require.True(t, extended.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+	})
+
+	t.Run("AnotherOrder", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetReservation(gomock.Any(), reservation.ReservationID).Return(reservation, nil)
+
+		err := inventoryUC.ReleaseReservation(context.Background(), reservation.ReservationID, uuid.New())
+		require.True(t, errors.Is(err, httpErrors.Conflict))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetReservation(gomock.Any(), reservation.ReservationID).Return(nil, nil)
+
+		err := inventoryUC.CommitReservation(context.Background(), reservation.ReservationID, reservation.OrderID, "scheduler", models.AllocationOptions{})
+		require.True(t, errors.Is(err, httpErrors.NotFound))
+	})
+}
+
+func TestInventoryUC_ImportItems(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	knownItem, unknownItem := uuid.New(), uuid.New()
+	rows := []*models.InventoryImportRow{
+		{Line: 2, Item: &models.InventoryItem{UUID: knownItem, Qty: 5}},
+		{Line: 3, Item: &models.InventoryItem{UUID: unknownItem, Qty: 1}},
+		{Line: 4, Item: &models.InventoryItem{UUID: knownItem, WarehouseID: uuid.New(), Qty: 2}},
+		{Line: 5, Item: &models.InventoryItem{UUID: knownItem, Qty: 0}},
+		{Line: 6, Item: &models.InventoryItem{UUID: knownItem, WarehouseID: models.DefaultWarehouseID, Qty: 3}},
+	}
+	change := models.StockChange{Reason: models.InventoryMovementReasonReceipt, Actor: "import"}
+
+	mockInventoryRepo.EXPECT().GetWarehouses(gomock.Any()).Return([]*models.Warehouse{{WarehouseID: models.DefaultWarehouseID}}, nil)
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), knownItem).Return(&models.Product{}, nil)
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), unknownItem).Return(nil, errors.Wrap(httpErrors.NotFound, "product"))
+	stock := []*models.InventoryItem{{UUID: knownItem, WarehouseID: models.DefaultWarehouseID, Qty: 8}}
+	mockInventoryRepo.EXPECT().AddItems(gomock.Any(), stock, change).Return(stock, nil)
+	mockRedisRepo.EXPECT().DeleteItemCtx(gomock.Any(), knownItem.String()).Return(nil)
+	mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), knownItem.String()).Return(&models.InventoryItem{UUID: knownItem, Qty: 8}, nil)
+	mockInventoryRepo.EXPECT().GetReservedQty(gomock.Any(), knownItem).Return(0, nil)
+	mockRedisRepo.EXPECT().PublishItemsCtx(gomock.Any(), []*models.InventoryItem{{UUID: knownItem, Qty: 8}}).Return(nil)
+
+	result, err := inventoryUC.ImportItems(context.Background(), rows, change)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Imported)
+	require.Len(t, result.Errors, 3)
+	require.Equal(t, 3, result.Errors[0].Line)
+	require.Equal(t, 4, result.Errors[1].Line)
+	require.Equal(t, 5, result.Errors[2].Line)
+}
+
+func TestInventoryUC_AdjustStock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	itemID := uuid.New()
+
+	t.Run("InvalidReason", func(t *testing.T) {
+		adjustment := &models.StockAdjustment{Delta: 1, Reason: models.InventoryMovementReasonOrder}
+
+		_, err := inventoryUC.AdjustStock(context.Background(), itemID, adjustment, "admin@mail.com")
+		var restErr httpErrors.RestErr
+		require.True(t, errors.As(err, &restErr))
+		require.Equal(t, http.StatusBadRequest, restErr.Status())
+	})
+
+	t.Run("NotEnoughStock", func(t *testing.T) {
+		adjustment := &models.StockAdjustment{Delta: -5}
+		stocks := []*models.InventoryItem{{UUID: itemID, WarehouseID: models.DefaultWarehouseID, Qty: 5}}
+		change := models.StockChange{Reason: models.InventoryMovementReasonAdjustment, Actor: "admin@mail.com"}
+		mockInventoryRepo.EXPECT().RemoveItems(gomock.Any(), stocks, change).
+			Return(&models.StockRemoval{Short: []*models.InventoryItem{{UUID: itemID, WarehouseID: models.DefaultWarehouseID, Qty: 2}}}, nil)
+
+		_, err := inventoryUC.AdjustStock(context.Background(), itemID, adjustment, "admin@mail.com")
+		var restErr httpErrors.RestErr
+		require.True(t, errors.As(err, &restErr))
+		require.Equal(t, http.StatusConflict, restErr.Status())
+	})
+}
+
+func TestInventoryUC_RemoveItems_LowStock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{LowStock: config.LowStock{DefaultThreshold: 5}}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	change := models.StockChange{Reason: models.InventoryMovementReasonAdjustment}
+	removeItem := func(itemID uuid.UUID, qty int, left int) {
+		stocks := []*models.InventoryItem{{UUID: itemID, WarehouseID: models.DefaultWarehouseID, Qty: qty}}
+		mockInventoryRepo.EXPECT().RemoveItems(gomock.Any(), stocks, change).
+			Return(&models.StockRemoval{
+				Items:  []*models.InventoryItem{{UUID: itemID, WarehouseID: models.DefaultWarehouseID, Qty: left}},
+				Levels: []*models.StockLevel{{ItemID: itemID, Before: left + qty, After: left}},
+			}, nil)
+		mockRedisRepo.EXPECT().DeleteItemCtx(gomock.Any(), itemID.String()).Return(nil)
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), itemID.String()).Return(&models.InventoryItem{UUID: itemID, Qty: left}, nil)
+		mockInventoryRepo.EXPECT().GetReservedQty(gomock.Any(), itemID).Return(0, nil)
+		mockRedisRepo.EXPECT().PublishItemsCtx(gomock.Any(), []*models.InventoryItem{{UUID: itemID, Qty: left}}).Return(nil)
+
+		_, shortItems, err := inventoryUC.RemoveItems(context.Background(), stocks, change)
+		require.NoError(t, err)
+		require.Empty(t, shortItems)
+	}
+
+	t.Run("BelowDefaultThreshold", func(t *testing.T) {
+		itemID := uuid.New()
+		mockInventoryRepo.EXPECT().GetThresholds(gomock.Any(), []uuid.UUID{itemID}).Return(nil, nil)
+		mockPublisher.EXPECT().PublishLowStock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *models.LowStockEvent) error {
+			require.Equal(t, itemID, event.ItemID)
+			require.Equal(t, models.LowStockKindBelowThreshold, event.Kind)
+			require.Equal(t, 4, event.Qty)
+			require.Equal(t, 5, event.Threshold)
+			return nil
+		})
+
+		removeItem(itemID, 3, 4)
+	})
+
+	t.Run("AlreadyBelowThreshold", func(t *testing.T) {
+		itemID := uuid.New()
+		mockInventoryRepo.EXPECT().GetThresholds(gomock.Any(), []uuid.UUID{itemID}).Return(nil, nil)
+
+		removeItem(itemID, 1, 3)
+	})
+
+	t.Run("ItemThreshold", func(t *testing.T) {
+		itemID := uuid.New()
+		mockInventoryRepo.EXPECT().GetThresholds(gomock.Any(), []uuid.UUID{itemID}).
+			Return([]*models.ItemThreshold{{ItemID: itemID, Threshold: 20}}, nil)
+		mockPublisher.EXPECT().PublishLowStock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *models.LowStockEvent) error {
+			require.Equal(t, models.LowStockKindBelowThreshold, event.Kind)
+			require.Equal(t, 20, event.Threshold)
+			return nil
+		})
+
+		removeItem(itemID, 5, 15)
+	})
+
+	t.Run("OutOfStock", func(t *testing.T) {
+		itemID := uuid.New()
+		mockInventoryRepo.EXPECT().GetThresholds(gomock.Any(), []uuid.UUID{itemID}).Return(nil, nil)
+		mockPublisher.EXPECT().PublishLowStock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *models.LowStockEvent) error {
+			require.Equal(t, models.LowStockKindOutOfStock, event.Kind)
+			require.Equal(t, 0, event.Qty)
+			return nil
+		})
+
+		removeItem(itemID, 2, 0)
+	})
+}
+
+func TestInventoryUC_GetItemByID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	t.Run("Cached", func(t *testing.T) {
+		item := &models.InventoryItem{UUID: uuid.New(), Qty: 4}
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), item.UUID.String()).Return(item, nil)
+
+		inventoryItem, err := inventoryUC.GetItemByID(context.Background(), item.UUID)
+		require.NoError(t, err)
+		require.Equal(t, item, inventoryItem)
+	})
+
+	t.Run("FromStorage", func(t *testing.T) {
+		item := &models.InventoryItem{UUID: uuid.New(), Qty: 4}
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), item.UUID.String()).Return(nil, nil)
+		mockInventoryRepo.EXPECT().GetItemByID(gomock.Any(), item.UUID).Return(item, nil)
+		mockRedisRepo.EXPECT().SetItemCtx(gomock.Any(), item.UUID.String(), cacheDuration, item).Return(nil)
+
+		inventoryItem, err := inventoryUC.GetItemByID(context.Background(), item.UUID)
+		require.NoError(t, err)
+		require.Equal(t, item, inventoryItem)
+	})
+}
+
+func TestInventoryUC_ReconcileStock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	drift := &models.StockDrift{ItemID: uuid.New(), WarehouseID: models.DefaultWarehouseID, StockQty: 7, LedgerQty: 5}
+
+	t.Run("DryRun", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().GetStockDrifts(gomock.Any()).Return([]*models.StockDrift{drift}, nil)
+
+		drifts, err := inventoryUC.ReconcileStock(context.Background(), false)
+		require.NoError(t, err)
+		require.Equal(t, []*models.StockDrift{drift}, drifts)
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		mockInventoryRepo.EXPECT().ReconcileStock(gomock.Any()).Return([]*models.StockDrift{drift}, nil)
+		mockRedisRepo.EXPECT().DeleteItemCtx(gomock.Any(), drift.ItemID.String()).Return(nil)
+		mockRedisRepo.EXPECT().GetByIDCtx(gomock.Any(), drift.ItemID.String()).Return(nil, nil)
+		mockInventoryRepo.EXPECT().GetItemByID(gomock.Any(), drift.ItemID).Return(&models.InventoryItem{UUID: drift.ItemID, Qty: 5}, nil)
+		mockRedisRepo.EXPECT().SetItemCtx(gomock.Any(), drift.ItemID.String(), cacheDuration, gomock.Any()).Return(nil)
+		mockInventoryRepo.EXPECT().GetReservedQty(gomock.Any(), drift.ItemID).Return(1, nil)
+		mockRedisRepo.EXPECT().PublishItemsCtx(gomock.Any(), []*models.InventoryItem{{UUID: drift.ItemID, Qty: 4}}).Return(nil)
+
+		drifts, err := inventoryUC.ReconcileStock(context.Background(), true)
+		require.NoError(t, err)
+		require.Equal(t, []*models.StockDrift{drift}, drifts)
+	})
+}
+
+func TestInventoryUC_WatchItems(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	mockInventoryRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockPublisher := mock.NewMockPublisher(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	inventoryUC := NewInventoryUseCase(cfg, mockInventoryRepo, mockRedisRepo, mockPublisher, mockProductClient, apiLogger)
+
+	t.Run("Filtered", func(t *testing.T) {
+		watchedID := uuid.New()
+		published := make(chan *models.InventoryItem, 2)
+		published <- &models.InventoryItem{UUID: uuid.New(), Qty: 1}
+		published <- &models.InventoryItem{UUID: watchedID, Qty: 2}
+		close(published)
+		mockRedisRepo.EXPECT().SubscribeItemsCtx(gomock.Any()).Return((<-chan *models.InventoryItem)(published), nil)
+
+		changes, err := inventoryUC.WatchItems(context.Background(), []uuid.UUID{watchedID})
+		require.NoError(t, err)
+
+		received := make([]*models.InventoryItem, 0)
+		for item := range changes {
+			received = append(received, item)
+		}
+		require.Equal(t, []*models.InventoryItem{{UUID: watchedID, Qty: 2}}, received)
+	})
+}
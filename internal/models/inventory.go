@@ -1,8 +1,222 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
 
+// Warehouse holding stock which was stored before warehouses were introduced
+var DefaultWarehouseID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Item stock, warehouse is empty for stock summed over all warehouses
 type InventoryItem struct {
-	UUID uuid.UUID
-	Qty  int
+	UUID        uuid.UUID `db:"item_id"`
+	WarehouseID uuid.UUID `db:"warehouse_id"`
+	Qty         int       `db:"qty"`
+}
+
+// Stock held for a pending order until it is committed, released or expired
+type InventoryReservation struct {
+	ReservationID uuid.UUID        `json:"reservation_id" db:"reservation_id"`
+	OrderID       uuid.UUID        `json:"order_id" db:"order_id"`
+	Items         []*InventoryItem `json:"items" db:"-"`
+	ExpiresAt     time.Time        `json:"expires_at" db:"expires_at"`
+}
+
+type Warehouse struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" db:"warehouse_id"`
+	Name        string    `json:"name" db:"name" validate:"required,lte=250"`
+	Address     string    `json:"address" db:"address" validate:"omitempty,lte=500"`
+	Latitude    float64   `json:"latitude" db:"latitude" validate:"gte=-90,lte=90"`
+	Longitude   float64   `json:"longitude" db:"longitude" validate:"gte=-180,lte=180"`
+	CreatedAt   time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Geographic point in degrees
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Great-circle distance to other point in kilometers
+func (l Location) DistanceTo(other Location) float64 {
+	const earthRadius = 6371.0
+	lat1, lat2 := l.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - l.Longitude) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Order of warehouses to take item stock from
+type AllocationStrategy string
+
+const (
+	// Warehouses closest to the location first
+	AllocationStrategyNearest AllocationStrategy = "nearest"
+	// Warehouses with the largest item stock first
+	AllocationStrategyMostStock AllocationStrategy = "most-stock"
+)
+
+// Parse allocation strategy name, empty name is most-stock
+func ParseAllocationStrategy(value string) (AllocationStrategy, error) {
+	switch AllocationStrategy(value) {
+	case "", AllocationStrategyMostStock:
+		return AllocationStrategyMostStock, nil
+	case AllocationStrategyNearest:
+		return AllocationStrategyNearest, nil
+	}
+	return "", errors.New("unknown allocation strategy")
+}
+
+// Allocation parameters, location is required by nearest strategy
+type AllocationOptions struct {
+	Strategy AllocationStrategy
+	Location *Location
+}
+
+// Why item stock changed
+type InventoryMovementReason string
+
+const (
+	InventoryMovementReasonReceipt    InventoryMovementReason = "receipt"
+	InventoryMovementReasonOrder      InventoryMovementReason = "order"
+	InventoryMovementReasonAdjustment InventoryMovementReason = "adjustment"
+	InventoryMovementReasonReturn     InventoryMovementReason = "return"
+)
+
+// Stock ledger entry, stock is the sum of item deltas in a warehouse
+type InventoryMovement struct {
+	MovementID  uuid.UUID               `json:"movement_id" db:"movement_id"`
+	ItemID      uuid.UUID               `json:"item_id" db:"item_id"`
+	WarehouseID uuid.UUID               `json:"warehouse_id" db:"warehouse_id"`
+	Delta       int                     `json:"delta" db:"delta"`
+	Reason      InventoryMovementReason `json:"reason" db:"reason"`
+	OrderID     *uuid.UUID              `json:"order_id,omitempty" db:"order_id"`
+	Actor       string                  `json:"actor" db:"actor"`
+	CreatedAt   time.Time               `json:"created_at" db:"created_at"`
+}
+
+// Source of stock change written to ledger entries of every changed item
+type StockChange struct {
+	Reason  InventoryMovementReason
+	OrderID *uuid.UUID
+	Actor   string
+}
+
+// Item stock in all warehouses around a change
+type StockLevel struct {
+	ItemID uuid.UUID
+	Before int
+	After  int
+}
+
+// Outcome of stock removal, nothing is removed when some items are short
+type StockRemoval struct {
+	Items  []*InventoryItem
+	Levels []*StockLevel
+	Short  []*InventoryItem
+}
+
+// Movement list filter, empty fields are not applied
+type InventoryMovementFilter struct {
+	ItemID      *uuid.UUID
+	WarehouseID *uuid.UUID
+	OrderID     *uuid.UUID
+	Reason      InventoryMovementReason
+}
+
+// Movement list page
+type InventoryMovementList struct {
+	TotalCount int                  `json:"total_count"`
+	TotalPages int                  `json:"total_pages"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	HasMore    bool                 `json:"has_more"`
+	Movements  []*InventoryMovement `json:"movements"`
+}
+
+// Difference between stored stock and stock rebuilt from ledger
+type StockDrift struct {
+	ItemID      uuid.UUID `json:"item_id" db:"item_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id" db:"warehouse_id"`
+	StockQty    int       `json:"stock_qty" db:"stock_qty"`
+	LedgerQty   int       `json:"ledger_qty" db:"ledger_qty"`
+}
+
+// Item stock below threshold raises low stock alert
+type ItemThreshold struct {
+	ItemID    uuid.UUID `json:"item_id" db:"item_id"`
+	Threshold int       `json:"threshold" db:"threshold"`
+}
+
+// AMQP message type of low stock alerts
+const LowStockEventType = "inventory.low_stock"
+
+type LowStockKind string
+
+const (
+	LowStockKindBelowThreshold LowStockKind = "below_threshold"
+	LowStockKindOutOfStock     LowStockKind = "out_of_stock"
+)
+
+// Alert about item stock crossing below its threshold or running out
+type LowStockEvent struct {
+	ItemID     uuid.UUID    `json:"item_id"`
+	Kind       LowStockKind `json:"kind"`
+	Qty        int          `json:"qty"`
+	Threshold  int          `json:"threshold"`
+	OccurredAt time.Time    `json:"occurred_at"`
+}
+
+// Row of bulk stock import, line refers to the source file
+type InventoryImportRow struct {
+	Line int
+	Item *InventoryItem
+}
+
+// Rejected row of bulk stock import
+type InventoryImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Result of bulk stock import, rows with errors are skipped
+type InventoryImportResult struct {
+	Imported int                     `json:"imported"`
+	Errors   []*InventoryImportError `json:"errors"`
+}
+
+// Stock of item in warehouse
+type WarehouseStock struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Qty         int       `json:"qty"`
+}
+
+// Item stock summed over warehouses, available quantity excludes reserved stock
+type InventoryItemDetails struct {
+	ItemID     uuid.UUID         `json:"item_id"`
+	Qty        int               `json:"qty"`
+	Available  int               `json:"available"`
+	Warehouses []*WarehouseStock `json:"warehouses,omitempty"`
+}
+
+// Paginated item stocks
+type InventoryItemList struct {
+	TotalCount int                     `json:"total_count"`
+	TotalPages int                     `json:"total_pages"`
+	Page       int                     `json:"page"`
+	Size       int                     `json:"size"`
+	HasMore    bool                    `json:"has_more"`
+	Items      []*InventoryItemDetails `json:"items"`
+}
+
+// Manual stock correction of item in warehouse, negative delta takes stock out
+type StockAdjustment struct {
+	WarehouseID uuid.UUID               `json:"warehouse_id"`
+	Delta       int                     `json:"delta" validate:"required"`
+	Reason      InventoryMovementReason `json:"reason" validate:"omitempty,oneof=receipt adjustment return"`
 }
@@ -1,6 +1,12 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type OrderStatus int64
 
@@ -35,25 +41,150 @@ func (s OrderStatus) ToString() string {
 	return ""
 }
 
+// Parse order status from its number or name
+func ParseOrderStatus(value string) (OrderStatus, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		if n > int(OrderStatusUndefined) && n <= int(OrderStatusCancelled) {
+			return OrderStatus(n), nil
+		}
+		return OrderStatusUndefined, errors.New("unknown order status")
+	}
+	for s := OrderStatusCreated; s <= OrderStatusCancelled; s++ {
+		if s.ToString() == strings.ToLower(value) {
+			return s, nil
+		}
+	}
+	return OrderStatusUndefined, errors.New("unknown order status")
+}
+
+type OrderStatusActor string
+
+const (
+	OrderStatusActorScheduler OrderStatusActor = "scheduler"
+	OrderStatusActorUser      OrderStatusActor = "user"
+	OrderStatusActorAdmin     OrderStatusActor = "admin"
+)
+
+// Order status transition record
+type OrderStatusHistory struct {
+	HistoryID  uuid.UUID        `json:"history_id" db:"history_id"`
+	OrderId    uuid.UUID        `json:"order_id" db:"order_id"`
+	FromStatus OrderStatus      `json:"from_status" db:"from_status"`
+	ToStatus   OrderStatus      `json:"to_status" db:"to_status"`
+	Actor      OrderStatusActor `json:"actor" db:"actor"`
+	ActorID    *uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	Reason     string           `json:"reason" db:"reason"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
+
 type OrderStatusNotify struct {
 	OrderId       uuid.UUID   `json:"order_id"`
 	Status        OrderStatus `json:"status"`
 	StatusMessage string      `json:"status_message"`
 }
 
+// Order processing task, status is the stage order is expected to be in
+type OrderTask struct {
+	OrderId uuid.UUID   `json:"order_id" db:"order_id"`
+	Status  OrderStatus `json:"status" db:"status"`
+}
+
+// Order fulfilment saga step, steps run in declaration order
+type OrderSagaStep int
+
+const (
+	OrderSagaStepReserve OrderSagaStep = iota
+	OrderSagaStepCharge
+	OrderSagaStepPackage
+	OrderSagaStepShip
+	OrderSagaStepDone
+)
+
+func (s OrderSagaStep) ToString() string {
+	switch s {
+	case OrderSagaStepReserve:
+		return "reserve"
+	case OrderSagaStepCharge:
+		return "charge"
+	case OrderSagaStepPackage:
+		return "package"
+	case OrderSagaStepShip:
+		return "ship"
+	case OrderSagaStepDone:
+		return "done"
+	}
+	return ""
+}
+
+type OrderSagaState int
+
+const (
+	OrderSagaStateUndefined OrderSagaState = iota
+	OrderSagaStateRunning
+	OrderSagaStateCompensating
+	OrderSagaStateCompleted
+	OrderSagaStateCompensated
+)
+
+// Persisted order fulfilment saga. Step is the next step to run while saga is running
+// and the number of steps left to compensate while it is compensating.
+// Cancel fields keep who cancelled the order and why until saga is compensated
+type OrderSaga struct {
+	OrderId       uuid.UUID        `json:"order_id" db:"order_id"`
+	Step          OrderSagaStep    `json:"step" db:"step"`
+	State         OrderSagaState   `json:"state" db:"state"`
+	ReservationID *uuid.UUID       `json:"reservation_id,omitempty" db:"reservation_id"`
+	PaymentID     *string          `json:"payment_id,omitempty" db:"payment_id"`
+	ShipmentID    *string          `json:"shipment_id,omitempty" db:"shipment_id"`
+	Failure       string           `json:"failure,omitempty" db:"failure"`
+	CancelActor   OrderStatusActor `json:"cancel_actor,omitempty" db:"cancel_actor"`
+	CancelActorID *uuid.UUID       `json:"cancel_actor_id,omitempty" db:"cancel_actor_id"`
+	CancelReason  string           `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	FencingToken  int64            `json:"-" db:"fencing_token"`
+	CreatedAt     time.Time        `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at,omitempty" db:"updated_at"`
+}
+
 type Order struct {
-	OrderId       uuid.UUID    `json:"order_id" validate:"omitempty"`
-	Status        OrderStatus  `json:"status"`
-	StatusMessage string       `json:"status_message"`
-	Sum           int          `json:"sum" validate:"omitempty"`
-	OrderList     []*OrderItem `json:"order_list"`
+	OrderId       uuid.UUID    `json:"order_id" db:"order_id" validate:"omitempty"`
+	UserID        *uuid.UUID   `json:"user_id,omitempty" db:"user_id"`
+	ReservationID *uuid.UUID   `json:"reservation_id,omitempty" db:"reservation_id"`
+	Status        OrderStatus  `json:"status" db:"status"`
+	StatusMessage string       `json:"status_message" db:"status_message"`
+	StatusReason  string       `json:"status_reason,omitempty" db:"-" validate:"max=256"`
+	Sum           int          `json:"sum" db:"sum" validate:"omitempty"`
+	OrderList     []*OrderItem `json:"order_list" db:"-" validate:"dive"`
+	FencingToken  int64        `json:"-" db:"fencing_token"`
+	CreatedAt     time.Time    `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Order list filter, empty fields are not applied.
+// CreatedTo bound is inclusive, CreatedBefore bound is exclusive
+type OrderFilter struct {
+	Status        OrderStatus
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CreatedBefore *time.Time
+	UserID        *uuid.UUID
+	MinSum        int
+}
+
+// All Orders response
+type OrderList struct {
+	TotalCount int      `json:"total_count"`
+	TotalPages int      `json:"total_pages"`
+	Page       int      `json:"page"`
+	Size       int      `json:"size"`
+	HasMore    bool     `json:"has_more"`
+	Orders     []*Order `json:"orders"`
 }
 
 type OrderItem struct {
-	ItemId uuid.UUID `json:"item_id" validate:"omitempty"`
-	Cost   int       `json:"cost" validate:"min=1"`
-	Qty    int       `json:"qty" validate:"min=1"`
-	Sum    int       `json:"sum" validate:"omitempty"`
+	ItemId uuid.UUID `json:"item_id" db:"item_id" validate:"omitempty"`
+	Cost   int       `json:"cost" db:"cost" validate:"omitempty"`
+	Qty    int       `json:"qty" db:"qty" validate:"min=1"`
+	Sum    int       `json:"sum" db:"sum" validate:"omitempty"`
 }
 
 func (o *Order) CalculateSum() {
@@ -0,0 +1,10 @@
+package order
+
+import (
+	"context"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type Consumer interface {
+	HandleMessage(ctx context.Context, message amqp.Delivery) error
+}
@@ -0,0 +1,54 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	notification "github.com/engineerXIII/maiSystemBackend/internal/notification"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Handles messages received from notification queue
+type notificationConsumer struct {
+	cfg    *config.Config
+	logger logger.Logger
+}
+
+func NewNotificationConsumer(cfg *config.Config, logger logger.Logger) notification.Consumer {
+	return &notificationConsumer{cfg: cfg, logger: logger}
+}
+
+func (c *notificationConsumer) HandleMessage(ctx context.Context, message amqp.Delivery) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "notificationConsumer.HandleMessage")
+	defer span.Finish()
+
+	switch message.Type {
+	case models.LowStockEventType:
+		return c.handleLowStock(ctx, message.Body)
+	default:
+		c.logger.Infof("AMQP received a message: %s", message.Body)
+		return nil
+	}
+}
+
+func (c *notificationConsumer) handleLowStock(ctx context.Context, body []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "notificationConsumer.handleLowStock")
+	defer span.Finish()
+
+	event := &models.LowStockEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return errors.Wrap(err, "notificationConsumer.handleLowStock.json.Unmarshal")
+	}
+
+	switch event.Kind {
+	case models.LowStockKindOutOfStock:
+		c.logger.Warnf("Item %s is out of stock", event.ItemID)
+	default:
+		c.logger.Warnf("Item %s stock %d is below threshold %d", event.ItemID, event.Qty, event.Threshold)
+	}
+	return nil
+}
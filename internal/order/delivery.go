@@ -7,4 +7,7 @@ type Handlers interface {
 	Update() echo.HandlerFunc
 	GetByID() echo.HandlerFunc
 	Delete() echo.HandlerFunc
+	GetOrders() echo.HandlerFunc
+	GetMyOrders() echo.HandlerFunc
+	GetHistory() echo.HandlerFunc
 }
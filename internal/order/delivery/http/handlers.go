@@ -11,6 +11,8 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/opentracing/opentracing-go"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type orderHandlers struct {
@@ -149,3 +151,168 @@ func (h orderHandlers) Delete() echo.HandlerFunc {
 		return c.NoContent(http.StatusOK)
 	}
 }
+
+// GetOrders godoc
+// @Summary Get order list
+// @Description Get order list with filters handler
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param page query int flase "page number" Format(page)
+// @Param size query int flase "size of page" Format(size)
+// @Param status query string flase "order status number or name"
+// @Param created_from query string flase "created at lower bound, RFC3339 or YYYY-MM-DD"
+// @Param created_to query string flase "created at upper bound, RFC3339 or YYYY-MM-DD"
+// @Param user_id query string flase "owner user id"
+// @Param min_sum query int flase "minimal order sum"
+// @Success 200 {object} models.OrderList
+// @Router /order [get]
+func (h orderHandlers) GetOrders() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "orderHandlers.GetOrders")
+		defer span.Finish()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		filter, err := getOrderFilterFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		orderList, err := h.orderUC.GetOrders(ctx, filter, pq)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, orderList)
+	}
+}
+
+// GetMyOrders godoc
+// @Summary Get current user orders
+// @Description Get orders of the authenticated user handler
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param page query int flase "page number" Format(page)
+// @Param size query int flase "size of page" Format(size)
+// @Success 200 {object} models.OrderList
+// @Router /order/my [get]
+func (h orderHandlers) GetMyOrders() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "orderHandlers.GetMyOrders")
+		defer span.Finish()
+
+		pq, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		orderList, err := h.orderUC.GetMyOrders(ctx, pq)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, orderList)
+	}
+}
+
+// GetHistory godoc
+// @Summary Get order status history
+// @Description Get order status transitions handler
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "order_id"
+// @Success 200 {array} models.OrderStatusHistory
+// @Router /order/{id}/history [get]
+func (h orderHandlers) GetHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		span, ctx := opentracing.StartSpanFromContext(utils.GetRequestCtx(c), "orderHandlers.GetHistory")
+		defer span.Finish()
+
+		orderUUID, err := uuid.Parse(c.Param("order_id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		history, err := h.orderUC.GetStatusHistory(ctx, orderUUID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return c.JSON(httpErrors.ErrorResponse(err))
+		}
+
+		return c.JSON(http.StatusOK, history)
+	}
+}
+
+// Get order list filter from query params
+func getOrderFilterFromCtx(c echo.Context) (*models.OrderFilter, error) {
+	filter := &models.OrderFilter{}
+
+	if value := c.QueryParam("status"); value != "" {
+		status, err := models.ParseOrderStatus(value)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err)
+		}
+		filter.Status = status
+	}
+
+	if value := c.QueryParam("created_from"); value != "" {
+		date, _, err := parseDate(value)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err)
+		}
+		filter.CreatedFrom = &date
+	}
+
+	if value := c.QueryParam("created_to"); value != "" {
+		date, dateOnly, err := parseDate(value)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err)
+		}
+		// Plain date covers the whole day
+		if dateOnly {
+			nextDay := date.AddDate(0, 0, 1)
+			filter.CreatedBefore = &nextDay
+		} else {
+			filter.CreatedTo = &date
+		}
+	}
+
+	if value := c.QueryParam("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err)
+		}
+		filter.UserID = &userID
+	}
+
+	if value := c.QueryParam("min_sum"); value != "" {
+		minSum, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, httpErrors.NewBadRequestError(err)
+		}
+		filter.MinSum = minSum
+	}
+
+	return filter, nil
+}
+
+// Parse RFC3339 timestamp or plain date, reports whether value is plain date
+func parseDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, false, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	return date, true, err
+}
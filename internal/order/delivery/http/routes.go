@@ -7,8 +7,11 @@ import (
 )
 
 func MapOrderRoutes(orderGroup *echo.Group, p order.Handlers, mw *middleware.MiddlewareManager) {
-	orderGroup.POST("/create", p.Create()) //, mw.AuthSessionMiddleware, mw.CSRF)
-	orderGroup.PUT("/:order_id", p.Update())
-	orderGroup.DELETE("/:order_id", p.Delete())
-	orderGroup.GET("/:order_id", p.GetByID())
+	orderGroup.POST("/create", p.Create(), mw.AuthSessionMiddleware, mw.CSRF)
+	orderGroup.PUT("/:order_id", p.Update(), mw.AuthSessionMiddleware, mw.CSRF)
+	orderGroup.DELETE("/:order_id", p.Delete(), mw.AuthSessionMiddleware, mw.CSRF)
+	orderGroup.GET("/my", p.GetMyOrders(), mw.AuthSessionMiddleware)
+	orderGroup.GET("/:order_id", p.GetByID(), mw.AuthSessionMiddleware)
+	orderGroup.GET("/:order_id/history", p.GetHistory(), mw.AuthSessionMiddleware)
+	orderGroup.GET("", p.GetOrders(), mw.AuthSessionMiddleware, mw.AdminMiddleware)
 }
@@ -0,0 +1,23 @@
+//go:generate mockgen -source gateway.go -destination mock/gateway_mock.go -package mock
+package order
+
+import (
+	"context"
+	"errors"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+// Request is refused by gateway, repeating it does not help
+var ErrDeclined = errors.New("declined by gateway")
+
+// Payment provider, order id identifies the charge so repeated charge of an order is not taken twice
+type PaymentGateway interface {
+	Charge(ctx context.Context, order *models.Order) (string, error)
+	Refund(ctx context.Context, order *models.Order, paymentID string) error
+}
+
+// Delivery provider, order id identifies the shipment so repeated shipment of an order is not created twice
+type ShippingGateway interface {
+	Ship(ctx context.Context, order *models.Order) (string, error)
+	CancelShipment(ctx context.Context, order *models.Order, shipmentID string) error
+}
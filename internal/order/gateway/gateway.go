@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+)
+
+// Payment and shipment ids of manual gateways refer to the order
+const (
+	paymentPrefix  = "cod-"
+	shipmentPrefix = "courier-"
+)
+
+// Cash on delivery, order is paid to courier, so charge only records the payment
+type cashOnDelivery struct {
+	logger logger.Logger
+}
+
+// Cash on delivery payment gateway constructor
+func NewCashOnDeliveryGateway(logger logger.Logger) order.PaymentGateway {
+	return &cashOnDelivery{logger: logger}
+}
+
+func (g *cashOnDelivery) Charge(ctx context.Context, value *models.Order) (string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "cashOnDelivery.Charge")
+	defer span.Finish()
+
+	g.logger.Infof("Order %s sum %d is collected on delivery", value.OrderId, value.Sum)
+	return paymentPrefix + value.OrderId.String(), nil
+}
+
+// Nothing was collected before delivery, so there is nothing to give back
+func (g *cashOnDelivery) Refund(ctx context.Context, value *models.Order, paymentID string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "cashOnDelivery.Refund")
+	defer span.Finish()
+
+	g.logger.Infof("Order %s payment %s is not collected anymore", value.OrderId, paymentID)
+	return nil
+}
+
+// Own courier service, packaged orders are handed over by warehouse staff
+type courier struct {
+	logger logger.Logger
+}
+
+// Courier shipping gateway constructor
+func NewCourierGateway(logger logger.Logger) order.ShippingGateway {
+	return &courier{logger: logger}
+}
+
+func (g *courier) Ship(ctx context.Context, value *models.Order) (string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "courier.Ship")
+	defer span.Finish()
+
+	g.logger.Infof("Order %s is handed over to courier", value.OrderId)
+	return shipmentPrefix + value.OrderId.String(), nil
+}
+
+func (g *courier) CancelShipment(ctx context.Context, value *models.Order, shipmentID string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "courier.CancelShipment")
+	defer span.Finish()
+
+	g.logger.Infof("Order %s shipment %s is cancelled", value.OrderId, shipmentID)
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:generate mockgen -source lock.go -destination mock/lock_mock.go -package mock
+package order
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Lock is held by another owner
+var ErrLocked = errors.New("resource is locked")
+
+// Distributed order lock, one owner across all replicas holds it at a time
+type Locker interface {
+	// Acquire lock of order, returned context is cancelled once lock is lost or released
+	Lock(ctx context.Context, orderID uuid.UUID) (Lock, context.Context, error)
+}
+
+// Acquired lock with fencing token, tokens grow with every acquisition of the order
+type Lock interface {
+	Token() int64
+	Unlock(ctx context.Context) error
+}
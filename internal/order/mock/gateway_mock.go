@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gateway.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentGateway is a mock of PaymentGateway interface.
+type MockPaymentGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentGatewayMockRecorder
+}
+
+// MockPaymentGatewayMockRecorder is the mock recorder for MockPaymentGateway.
+type MockPaymentGatewayMockRecorder struct {
+	mock *MockPaymentGateway
+}
+
+// NewMockPaymentGateway creates a new mock instance.
+func NewMockPaymentGateway(ctrl *gomock.Controller) *MockPaymentGateway {
+	mock := &MockPaymentGateway{ctrl: ctrl}
+	mock.recorder = &MockPaymentGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentGateway) EXPECT() *MockPaymentGatewayMockRecorder {
+	return m.recorder
+}
+
+// Charge mocks base method.
+func (m *MockPaymentGateway) Charge(ctx context.Context, order *models.Order) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Charge", ctx, order)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Charge indicates an expected call of Charge.
+func (mr *MockPaymentGatewayMockRecorder) Charge(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Charge", reflect.TypeOf((*MockPaymentGateway)(nil).Charge), ctx, order)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentGateway) Refund(ctx context.Context, order *models.Order, paymentID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, order, paymentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentGatewayMockRecorder) Refund(ctx, order, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentGateway)(nil).Refund), ctx, order, paymentID)
+}
+
+// MockShippingGateway is a mock of ShippingGateway interface.
+type MockShippingGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockShippingGatewayMockRecorder
+}
+
+// MockShippingGatewayMockRecorder is the mock recorder for MockShippingGateway.
+type MockShippingGatewayMockRecorder struct {
+	mock *MockShippingGateway
+}
+
+// NewMockShippingGateway creates a new mock instance.
+func NewMockShippingGateway(ctrl *gomock.Controller) *MockShippingGateway {
+	mock := &MockShippingGateway{ctrl: ctrl}
+	mock.recorder = &MockShippingGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShippingGateway) EXPECT() *MockShippingGatewayMockRecorder {
+	return m.recorder
+}
+
+// CancelShipment mocks base method.
+func (m *MockShippingGateway) CancelShipment(ctx context.Context, order *models.Order, shipmentID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelShipment", ctx, order, shipmentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelShipment indicates an expected call of CancelShipment.
+func (mr *MockShippingGatewayMockRecorder) CancelShipment(ctx, order, shipmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelShipment", reflect.TypeOf((*MockShippingGateway)(nil).CancelShipment), ctx, order, shipmentID)
+}
+
+// Ship mocks base method.
+func (m *MockShippingGateway) Ship(ctx context.Context, order *models.Order) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ship", ctx, order)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ship indicates an expected call of Ship.
+func (mr *MockShippingGatewayMockRecorder) Ship(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ship", reflect.TypeOf((*MockShippingGateway)(nil).Ship), ctx, order)
+}
@@ -38,90 +38,164 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, product *models.Product) (*models.Product, error) {
+func (m *MockRepository) Create(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, product)
-	ret0, _ := ret[0].(*models.Product)
+	ret := m.ctrl.Call(m, "Create", ctx, order, history)
+	ret0, _ := ret[0].(*models.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(ctx, product interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(ctx, order, history interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, order, history)
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(ctx context.Context, productID uuid.UUID) error {
+func (m *MockRepository) Delete(ctx context.Context, orderID uuid.UUID, token int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, productID)
+	ret := m.ctrl.Call(m, "Delete", ctx, orderID, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(ctx, productID interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, orderID, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, productID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, orderID, token)
 }
 
-// GetProductByID mocks base method.
-func (m *MockRepository) GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+// GetActiveOrderTasks mocks base method.
+func (m *MockRepository) GetActiveOrderTasks(ctx context.Context) ([]*models.OrderTask, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProductByID", ctx, productID)
-	ret0, _ := ret[0].(*models.Product)
+	ret := m.ctrl.Call(m, "GetActiveOrderTasks", ctx)
+	ret0, _ := ret[0].([]*models.OrderTask)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetProductByID indicates an expected call of GetProductByID.
-func (mr *MockRepositoryMockRecorder) GetProductByID(ctx, productID interface{}) *gomock.Call {
+// GetActiveOrderTasks indicates an expected call of GetActiveOrderTasks.
+func (mr *MockRepositoryMockRecorder) GetActiveOrderTasks(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockRepository)(nil).GetProductByID), ctx, productID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveOrderTasks", reflect.TypeOf((*MockRepository)(nil).GetActiveOrderTasks), ctx)
 }
 
-// GetProducts mocks base method.
-func (m *MockRepository) GetProducts(ctx context.Context, pq *utils.PaginationQuery) (*models.ProductList, error) {
+// GetOrderByID mocks base method.
+func (m *MockRepository) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProducts", ctx, pq)
-	ret0, _ := ret[0].(*models.ProductList)
+	ret := m.ctrl.Call(m, "GetOrderByID", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetProducts indicates an expected call of GetProducts.
-func (mr *MockRepositoryMockRecorder) GetProducts(ctx, pq interface{}) *gomock.Call {
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockRepositoryMockRecorder) GetOrderByID(ctx, orderID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockRepository)(nil).GetProducts), ctx, pq)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockRepository)(nil).GetOrderByID), ctx, orderID)
 }
 
-// SearchByName mocks base method.
-func (m *MockRepository) SearchByName(ctx context.Context, name string, query *utils.PaginationQuery) (*models.ProductList, error) {
+// GetOrders mocks base method.
+func (m *MockRepository) GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchByName", ctx, name, query)
-	ret0, _ := ret[0].(*models.ProductList)
+	ret := m.ctrl.Call(m, "GetOrders", ctx, filter, pq)
+	ret0, _ := ret[0].(*models.OrderList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SearchByName indicates an expected call of SearchByName.
-func (mr *MockRepositoryMockRecorder) SearchByName(ctx, name, query interface{}) *gomock.Call {
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockRepositoryMockRecorder) GetOrders(ctx, filter, pq interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByName", reflect.TypeOf((*MockRepository)(nil).SearchByName), ctx, name, query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockRepository)(nil).GetOrders), ctx, filter, pq)
+}
+
+// GetSaga mocks base method.
+func (m *MockRepository) GetSaga(ctx context.Context, orderID uuid.UUID) (*models.OrderSaga, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSaga", ctx, orderID)
+	ret0, _ := ret[0].(*models.OrderSaga)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSaga indicates an expected call of GetSaga.
+func (mr *MockRepositoryMockRecorder) GetSaga(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSaga", reflect.TypeOf((*MockRepository)(nil).GetSaga), ctx, orderID)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockRepository) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*models.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]*models.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockRepositoryMockRecorder) GetStatusHistory(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockRepository)(nil).GetStatusHistory), ctx, orderID)
+}
+
+// IssueFencingToken mocks base method.
+func (m *MockRepository) IssueFencingToken(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueFencingToken", ctx, orderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueFencingToken indicates an expected call of IssueFencingToken.
+func (mr *MockRepositoryMockRecorder) IssueFencingToken(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueFencingToken", reflect.TypeOf((*MockRepository)(nil).IssueFencingToken), ctx, orderID)
+}
+
+// SaveSaga mocks base method.
+func (m *MockRepository) SaveSaga(ctx context.Context, saga *models.OrderSaga, token int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSaga", ctx, saga, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSaga indicates an expected call of SaveSaga.
+func (mr *MockRepositoryMockRecorder) SaveSaga(ctx, saga, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSaga", reflect.TypeOf((*MockRepository)(nil).SaveSaga), ctx, saga, token)
 }
 
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, product *models.Product) (*models.Product, error) {
+func (m *MockRepository) Update(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, product)
-	ret0, _ := ret[0].(*models.Product)
+	ret := m.ctrl.Call(m, "Update", ctx, order, history)
+	ret0, _ := ret[0].(*models.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, product interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, order, history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, order, history)
+}
+
+// UpdateFenced mocks base method.
+func (m *MockRepository) UpdateFenced(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, token int64) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFenced", ctx, order, history, token)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFenced indicates an expected call of UpdateFenced.
+func (mr *MockRepositoryMockRecorder) UpdateFenced(ctx, order, history, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFenced", reflect.TypeOf((*MockRepository)(nil).UpdateFenced), ctx, order, history, token)
 }
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: queue.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+)
+
+// MockQueue is a mock of Queue interface.
+type MockQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueueMockRecorder
+}
+
+// MockQueueMockRecorder is the mock recorder for MockQueue.
+type MockQueueMockRecorder struct {
+	mock *MockQueue
+}
+
+// NewMockQueue creates a new mock instance.
+func NewMockQueue(ctrl *gomock.Controller) *MockQueue {
+	mock := &MockQueue{ctrl: ctrl}
+	mock.recorder = &MockQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueue) EXPECT() *MockQueueMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockQueue) Consume(ctx context.Context, consumer string, prefetch int) (<-chan amqp091.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, consumer, prefetch)
+	ret0, _ := ret[0].(<-chan amqp091.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockQueueMockRecorder) Consume(ctx, consumer, prefetch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockQueue)(nil).Consume), ctx, consumer, prefetch)
+}
+
+// Declare mocks base method.
+func (m *MockQueue) Declare() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Declare")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Declare indicates an expected call of Declare.
+func (mr *MockQueueMockRecorder) Declare() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Declare", reflect.TypeOf((*MockQueue)(nil).Declare))
+}
+
+// Publish mocks base method.
+func (m *MockQueue) Publish(ctx context.Context, task *models.OrderTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockQueueMockRecorder) Publish(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockQueue)(nil).Publish), ctx, task)
+}
+
+// PublishRetry mocks base method.
+func (m *MockQueue) PublishRetry(ctx context.Context, task *models.OrderTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishRetry", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishRetry indicates an expected call of PublishRetry.
+func (mr *MockQueueMockRecorder) PublishRetry(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishRetry", reflect.TypeOf((*MockQueue)(nil).PublishRetry), ctx, task)
+}
@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: redis_repository.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	order "github.com/engineerXIII/maiSystemBackend/internal/order"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockKeyIterator is a mock of KeyIterator interface.
+type MockKeyIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyIteratorMockRecorder
+}
+
+// MockKeyIteratorMockRecorder is the mock recorder for MockKeyIterator.
+type MockKeyIteratorMockRecorder struct {
+	mock *MockKeyIterator
+}
+
+// NewMockKeyIterator creates a new mock instance.
+func NewMockKeyIterator(ctrl *gomock.Controller) *MockKeyIterator {
+	mock := &MockKeyIterator{ctrl: ctrl}
+	mock.recorder = &MockKeyIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyIterator) EXPECT() *MockKeyIteratorMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockKeyIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockKeyIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockKeyIterator)(nil).Err))
+}
+
+// Key mocks base method.
+func (m *MockKeyIterator) Key() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Key")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Key indicates an expected call of Key.
+func (mr *MockKeyIteratorMockRecorder) Key() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Key", reflect.TypeOf((*MockKeyIterator)(nil).Key))
+}
+
+// Next mocks base method.
+func (m *MockKeyIterator) Next(ctx context.Context) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", ctx)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockKeyIteratorMockRecorder) Next(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockKeyIterator)(nil).Next), ctx)
+}
+
+// MockRedisRepository is a mock of RedisRepository interface.
+type MockRedisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisRepositoryMockRecorder
+}
+
+// MockRedisRepositoryMockRecorder is the mock recorder for MockRedisRepository.
+type MockRedisRepositoryMockRecorder struct {
+	mock *MockRedisRepository
+}
+
+// NewMockRedisRepository creates a new mock instance.
+func NewMockRedisRepository(ctrl *gomock.Controller) *MockRedisRepository {
+	mock := &MockRedisRepository{ctrl: ctrl}
+	mock.recorder = &MockRedisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrderCtx mocks base method.
+func (m *MockRedisRepository) DeleteOrderCtx(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrderCtx", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrderCtx indicates an expected call of DeleteOrderCtx.
+func (mr *MockRedisRepositoryMockRecorder) DeleteOrderCtx(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrderCtx", reflect.TypeOf((*MockRedisRepository)(nil).DeleteOrderCtx), ctx, key)
+}
+
+// DeleteOrderTaskCtx mocks base method.
+func (m *MockRedisRepository) DeleteOrderTaskCtx(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrderTaskCtx", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrderTaskCtx indicates an expected call of DeleteOrderTaskCtx.
+func (mr *MockRedisRepositoryMockRecorder) DeleteOrderTaskCtx(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrderTaskCtx", reflect.TypeOf((*MockRedisRepository)(nil).DeleteOrderTaskCtx), ctx, key)
+}
+
+// GetOrderByIDCtx mocks base method.
+func (m *MockRedisRepository) GetOrderByIDCtx(ctx context.Context, key string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByIDCtx", ctx, key)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByIDCtx indicates an expected call of GetOrderByIDCtx.
+func (mr *MockRedisRepositoryMockRecorder) GetOrderByIDCtx(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByIDCtx", reflect.TypeOf((*MockRedisRepository)(nil).GetOrderByIDCtx), ctx, key)
+}
+
+// OrderKeys mocks base method.
+func (m *MockRedisRepository) OrderKeys(batchSize int64) order.KeyIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderKeys", batchSize)
+	ret0, _ := ret[0].(order.KeyIterator)
+	return ret0
+}
+
+// OrderKeys indicates an expected call of OrderKeys.
+func (mr *MockRedisRepositoryMockRecorder) OrderKeys(batchSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderKeys", reflect.TypeOf((*MockRedisRepository)(nil).OrderKeys), batchSize)
+}
+
+// ScanOrderKeys mocks base method.
+func (m *MockRedisRepository) ScanOrderKeys(batchSize int64) order.KeyIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanOrderKeys", batchSize)
+	ret0, _ := ret[0].(order.KeyIterator)
+	return ret0
+}
+
+// ScanOrderKeys indicates an expected call of ScanOrderKeys.
+func (mr *MockRedisRepositoryMockRecorder) ScanOrderKeys(batchSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanOrderKeys", reflect.TypeOf((*MockRedisRepository)(nil).ScanOrderKeys), batchSize)
+}
+
+// SetOrderCtx mocks base method.
+func (m *MockRedisRepository) SetOrderCtx(ctx context.Context, key string, seconds int, news *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderCtx", ctx, key, seconds, news)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrderCtx indicates an expected call of SetOrderCtx.
+func (mr *MockRedisRepositoryMockRecorder) SetOrderCtx(ctx, key, seconds, news interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderCtx", reflect.TypeOf((*MockRedisRepository)(nil).SetOrderCtx), ctx, key, seconds, news)
+}
+
+// SetOrderTaskCtx mocks base method.
+func (m *MockRedisRepository) SetOrderTaskCtx(ctx context.Context, key string, seconds int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderTaskCtx", ctx, key, seconds)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOrderTaskCtx indicates an expected call of SetOrderTaskCtx.
+func (mr *MockRedisRepositoryMockRecorder) SetOrderTaskCtx(ctx, key, seconds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderTaskCtx", reflect.TypeOf((*MockRedisRepository)(nil).SetOrderTaskCtx), ctx, key, seconds)
+}
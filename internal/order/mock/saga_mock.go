@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: saga.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSaga is a mock of Saga interface.
+type MockSaga struct {
+	ctrl     *gomock.Controller
+	recorder *MockSagaMockRecorder
+}
+
+// MockSagaMockRecorder is the mock recorder for MockSaga.
+type MockSagaMockRecorder struct {
+	mock *MockSaga
+}
+
+// NewMockSaga creates a new mock instance.
+func NewMockSaga(ctrl *gomock.Controller) *MockSaga {
+	mock := &MockSaga{ctrl: ctrl}
+	mock.recorder = &MockSagaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSaga) EXPECT() *MockSagaMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockSaga) Cancel(ctx context.Context, order *models.Order, cancellation *models.OrderStatusHistory, token int64) (*models.OrderSaga, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, order, cancellation, token)
+	ret0, _ := ret[0].(*models.OrderSaga)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockSagaMockRecorder) Cancel(ctx, order, cancellation, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockSaga)(nil).Cancel), ctx, order, cancellation, token)
+}
+
+// Run mocks base method.
+func (m *MockSaga) Run(ctx context.Context, order *models.Order, token int64) (*models.OrderSaga, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, order, token)
+	ret0, _ := ret[0].(*models.OrderSaga)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSagaMockRecorder) Run(ctx, order, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSaga)(nil).Run), ctx, order, token)
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/engineerXIII/maiSystemBackend/internal/models"
+	utils "github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -65,6 +66,21 @@ func (mr *MockUseCaseMockRecorder) Delete(ctx, orderID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUseCase)(nil).Delete), ctx, orderID)
 }
 
+// GetMyOrders mocks base method.
+func (m *MockUseCase) GetMyOrders(ctx context.Context, pq *utils.PaginationQuery) (*models.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyOrders", ctx, pq)
+	ret0, _ := ret[0].(*models.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyOrders indicates an expected call of GetMyOrders.
+func (mr *MockUseCaseMockRecorder) GetMyOrders(ctx, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrders", reflect.TypeOf((*MockUseCase)(nil).GetMyOrders), ctx, pq)
+}
+
 // GetOrderByID mocks base method.
 func (m *MockUseCase) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +96,36 @@ func (mr *MockUseCaseMockRecorder) GetOrderByID(ctx, orderID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockUseCase)(nil).GetOrderByID), ctx, orderID)
 }
 
+// GetOrders mocks base method.
+func (m *MockUseCase) GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, filter, pq)
+	ret0, _ := ret[0].(*models.OrderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockUseCaseMockRecorder) GetOrders(ctx, filter, pq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockUseCase)(nil).GetOrders), ctx, filter, pq)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockUseCase) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*models.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, orderID)
+	ret0, _ := ret[0].([]*models.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockUseCaseMockRecorder) GetStatusHistory(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockUseCase)(nil).GetStatusHistory), ctx, orderID)
+}
+
 // Update mocks base method.
 func (m *MockUseCase) Update(ctx context.Context, order *models.Order) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,24 @@
+//go:generate mockgen -source pg_repository.go -destination mock/pg_repository_mock.go -package mock
+package order
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// Order repository
+type Repository interface {
+	Create(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error)
+	Update(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error)
+	UpdateFenced(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, token int64) (*models.Order, error)
+	IssueFencingToken(ctx context.Context, orderID uuid.UUID) (int64, error)
+	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
+	Delete(ctx context.Context, orderID uuid.UUID, token int64) error
+	GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error)
+	GetActiveOrderTasks(ctx context.Context) ([]*models.OrderTask, error)
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*models.OrderStatusHistory, error)
+	GetSaga(ctx context.Context, orderID uuid.UUID) (*models.OrderSaga, error)
+	SaveSaga(ctx context.Context, saga *models.OrderSaga, token int64) error
+}
@@ -0,0 +1,16 @@
+//go:generate mockgen -source queue.go -destination mock/queue_mock.go -package mock
+package order
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Durable queue of order processing tasks
+type Queue interface {
+	Declare() error
+	Publish(ctx context.Context, task *models.OrderTask) error
+	PublishRetry(ctx context.Context, task *models.OrderTask) error
+	Consume(ctx context.Context, consumer string, prefetch int) (<-chan amqp.Delivery, error)
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// Retry queue holds failed tasks for retry delay, then dead letters them back to processing queue
+const retrySuffix = ".retry"
+
+// Order processing queue on RabbitMQ
+type orderQueue struct {
+	amqpChannel *amqp.Channel
+	name        string
+	retryDelay  time.Duration
+}
+
+// Order queue constructor
+func NewOrderQueue(cfg *config.Config, amqpChannel *amqp.Channel) order.Queue {
+	return &orderQueue{
+		amqpChannel: amqpChannel,
+		name:        cfg.Processing.Queue,
+		retryDelay:  time.Second * time.Duration(cfg.Processing.RetryDelay),
+	}
+}
+
+// Declare durable processing and retry queues
+func (q *orderQueue) Declare() error {
+	if _, err := q.amqpChannel.QueueDeclare(q.name, true, false, false, false, nil); err != nil {
+		return errors.Wrap(err, "orderQueue.Declare.QueueDeclare")
+	}
+	_, err := q.amqpChannel.QueueDeclare(q.name+retrySuffix, true, false, false, false, amqp.Table{
+		"x-message-ttl":             q.retryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": q.name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "orderQueue.Declare.QueueDeclare.retry")
+	}
+	return nil
+}
+
+// Publish task to processing queue
+func (q *orderQueue) Publish(ctx context.Context, task *models.OrderTask) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderQueue.Publish")
+	defer span.Finish()
+
+	if err := q.publish(ctx, q.name, task); err != nil {
+		return errors.Wrap(err, "orderQueue.Publish")
+	}
+	return nil
+}
+
+// Publish task to retry queue, it returns to processing queue after retry delay
+func (q *orderQueue) PublishRetry(ctx context.Context, task *models.OrderTask) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderQueue.PublishRetry")
+	defer span.Finish()
+
+	if err := q.publish(ctx, q.name+retrySuffix, task); err != nil {
+		return errors.Wrap(err, "orderQueue.PublishRetry")
+	}
+	return nil
+}
+
+// Consume processing queue with manual acks, at most prefetch tasks are delivered unacknowledged
+func (q *orderQueue) Consume(ctx context.Context, consumer string, prefetch int) (<-chan amqp.Delivery, error) {
+	if err := q.amqpChannel.Qos(prefetch, 0, false); err != nil {
+		return nil, errors.Wrap(err, "orderQueue.Consume.Qos")
+	}
+	deliveries, err := q.amqpChannel.Consume(q.name, consumer, false, false, false, false, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderQueue.Consume")
+	}
+	go func() {
+		<-ctx.Done()
+		_ = q.amqpChannel.Cancel(consumer, false)
+	}()
+	return deliveries, nil
+}
+
+func (q *orderQueue) publish(ctx context.Context, queue string, task *models.OrderTask) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return errors.Wrap(err, "json.Marshal")
+	}
+	return q.amqpChannel.PublishWithContext(ctx,
+		"",
+		queue,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Body:         body,
+		})
+}
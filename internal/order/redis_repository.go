@@ -1,3 +1,4 @@
+//go:generate mockgen -source redis_repository.go -destination mock/redis_repository_mock.go -package mock
 package order
 
 import (
@@ -5,9 +6,23 @@ import (
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
 )
 
+// Iterator over cached order keys, keys are loaded in batches while iterating
+type KeyIterator interface {
+	Next(ctx context.Context) bool
+	Key() string
+	Err() error
+}
+
+// Order redis repository
 type RedisRepository interface {
 	GetOrderByIDCtx(ctx context.Context, key string) (*models.Order, error)
 	SetOrderCtx(ctx context.Context, key string, seconds int, news *models.Order) error
 	DeleteOrderCtx(ctx context.Context, key string) error
-	GetOrderKeysCtx(ctx context.Context) ([]string, error)
+	// Iterators take context on every step, so a long walk can be cancelled between batches
+	OrderKeys(batchSize int64) KeyIterator
+	// Keyspace scan finds cached orders missing in the index, it is meant for repair tooling
+	// and is not used by services, which rely on the index
+	ScanOrderKeys(batchSize int64) KeyIterator
+	SetOrderTaskCtx(ctx context.Context, key string, seconds int) (bool, error)
+	DeleteOrderTaskCtx(ctx context.Context, key string) error
 }
@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Order postgres repository
+type orderRepo struct {
+	db *sqlx.DB
+}
+
+// Order postgres repository constructor
+func NewOrderRepository(db *sqlx.DB) order.Repository {
+	return &orderRepo{db: db}
+}
+
+// Create order with its items and first status history entry in one transaction
+func (r *orderRepo) Create(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.Create")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Create.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var o models.Order
+	if err = tx.QueryRowxContext(
+		ctx,
+		createOrder,
+		order.OrderId,
+		order.UserID,
+		order.ReservationID,
+		&order.Status,
+		&order.StatusMessage,
+		&order.Sum,
+	).StructScan(&o); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Create.QueryRowxContext")
+	}
+
+	if err = r.createItems(ctx, tx, o.OrderId, order.OrderList); err != nil {
+		return nil, err
+	}
+	o.OrderList = order.OrderList
+
+	if err = r.createStatusHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Create.Commit")
+	}
+
+	return &o, nil
+}
+
+// Update order, history entry is written in the same transaction when status changed
+func (r *orderRepo) Update(ctx context.Context, order *models.Order, history *models.OrderStatusHistory) (*models.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.Update")
+	defer span.Finish()
+
+	return r.update(ctx, order, history, updateOrder, &order.Status, &order.StatusMessage, &order.Sum, &order.OrderId)
+}
+
+// Update order and its reservation on behalf of lock holder, writes with token other than the last issued one are rejected as conflict
+func (r *orderRepo) UpdateFenced(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, token int64) (*models.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.UpdateFenced")
+	defer span.Finish()
+
+	o, err := r.update(ctx, order, history, updateOrderFenced, &order.Status, &order.StatusMessage, &order.Sum, &order.OrderId, token, order.ReservationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, httpErrors.NewConflictError(errors.Errorf("orderRepo.UpdateFenced: order %s was changed by another process", order.OrderId))
+	}
+	return o, err
+}
+
+// Issue next fencing token of order, tokens are stored with order so they keep growing whatever happens to lock storage
+func (r *orderRepo) IssueFencingToken(ctx context.Context, orderID uuid.UUID) (int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.IssueFencingToken")
+	defer span.Finish()
+
+	var token int64
+	if err := r.db.GetContext(ctx, &token, issueFencingToken, orderID); err != nil {
+		return 0, errors.Wrap(err, "orderRepo.IssueFencingToken.GetContext")
+	}
+	return token, nil
+}
+
+// Update order row, replace its items and append history entry when set in one transaction
+func (r *orderRepo) update(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, query string, args ...interface{}) (*models.Order, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Update.BeginTxx")
+	}
+	defer tx.Rollback()
+
+	var o models.Order
+	if err = tx.QueryRowxContext(ctx, query, args...).StructScan(&o); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Update.QueryRowxContext")
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteOrderItems, order.OrderId); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Update.ExecContext.deleteOrderItems")
+	}
+	if err = r.createItems(ctx, tx, o.OrderId, order.OrderList); err != nil {
+		return nil, err
+	}
+	o.OrderList = order.OrderList
+
+	if history != nil {
+		if err = r.createStatusHistory(ctx, tx, history); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.Update.Commit")
+	}
+
+	return &o, nil
+}
+
+func (r *orderRepo) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.GetOrderByID")
+	defer span.Finish()
+
+	o := &models.Order{}
+	if err := r.db.GetContext(ctx, o, getOrderByID, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetOrderByID.GetContext")
+	}
+
+	o.OrderList = make([]*models.OrderItem, 0)
+	if err := r.db.SelectContext(ctx, &o.OrderList, getOrderItems, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetOrderByID.SelectContext")
+	}
+
+	return o, nil
+}
+
+// Delete order on behalf of lock holder, delete with token other than the last issued one is rejected as conflict
+func (r *orderRepo) Delete(ctx context.Context, orderID uuid.UUID, token int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.Delete")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(ctx, deleteOrder, orderID, token)
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.Delete.ExecContext")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.Delete.RowsAffected")
+	}
+
+	if rowsAffected == 0 {
+		return httpErrors.NewConflictError(errors.Errorf("orderRepo.Delete: order %s was changed by another process", orderID))
+	}
+
+	return nil
+}
+
+// Get ids of orders which are not completed or cancelled yet
+func (r *orderRepo) GetActiveOrderTasks(ctx context.Context) ([]*models.OrderTask, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.GetActiveOrderTasks")
+	defer span.Finish()
+
+	tasks := make([]*models.OrderTask, 0)
+	if err := r.db.SelectContext(
+		ctx,
+		&tasks,
+		getActiveOrderTasks,
+		models.OrderStatusCompleted,
+		models.OrderStatusCancelled,
+	); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetActiveOrderTasks.SelectContext")
+	}
+
+	return tasks, nil
+}
+
+func (r *orderRepo) GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.GetOrders")
+	defer span.Finish()
+
+	where, args := buildOrderFilter(filter)
+
+	var totalCount int
+	if err := r.db.GetContext(ctx, &totalCount, getTotalCount+where, args...); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetOrders.GetContext.totalCount")
+	}
+
+	if totalCount == 0 {
+		return &models.OrderList{
+			TotalCount: totalCount,
+			TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+			Page:       pq.GetPage(),
+			Size:       pq.GetSize(),
+			HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+			Orders:     make([]*models.Order, 0),
+		}, nil
+	}
+
+	query := fmt.Sprintf("%s%s ORDER BY created_at DESC OFFSET $%d LIMIT $%d", getOrders, where, len(args)+1, len(args)+2)
+	args = append(args, pq.GetOffset(), pq.GetLimit())
+
+	var orderList = make([]*models.Order, 0)
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetOrders.QueryxContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		o := &models.Order{OrderList: make([]*models.OrderItem, 0)}
+		if err = rows.StructScan(o); err != nil {
+			return nil, errors.Wrap(err, "orderRepo.GetOrders.StructScan")
+		}
+		orderList = append(orderList, o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetOrders.rows.Err")
+	}
+
+	if err = r.fillItems(ctx, orderList); err != nil {
+		return nil, err
+	}
+
+	return &models.OrderList{
+		TotalCount: totalCount,
+		TotalPages: utils.GetTotalPages(totalCount, pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), totalCount, pq.GetSize()),
+		Orders:     orderList,
+	}, nil
+}
+
+// Get order status transitions in chronological order
+func (r *orderRepo) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*models.OrderStatusHistory, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.GetStatusHistory")
+	defer span.Finish()
+
+	history := make([]*models.OrderStatusHistory, 0)
+	if err := r.db.SelectContext(ctx, &history, getStatusHistory, orderID); err != nil {
+		return nil, errors.Wrap(err, "orderRepo.GetStatusHistory.SelectContext")
+	}
+
+	return history, nil
+}
+
+// Get fulfilment saga of order, nil when saga is not started
+func (r *orderRepo) GetSaga(ctx context.Context, orderID uuid.UUID) (*models.OrderSaga, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.GetSaga")
+	defer span.Finish()
+
+	saga := &models.OrderSaga{}
+	if err := r.db.GetContext(ctx, saga, getOrderSaga, orderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "orderRepo.GetSaga.GetContext")
+	}
+
+	return saga, nil
+}
+
+// Save saga state on behalf of lock holder, writes with token older than the last one are rejected as conflict
+func (r *orderRepo) SaveSaga(ctx context.Context, saga *models.OrderSaga, token int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepo.SaveSaga")
+	defer span.Finish()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		saveOrderSaga,
+		&saga.OrderId,
+		&saga.Step,
+		&saga.State,
+		saga.ReservationID,
+		saga.PaymentID,
+		saga.ShipmentID,
+		&saga.Failure,
+		&saga.CancelActor,
+		saga.CancelActorID,
+		&saga.CancelReason,
+		token,
+	)
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.SaveSaga.ExecContext")
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.SaveSaga.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return httpErrors.NewConflictError(errors.Errorf("orderRepo.SaveSaga: saga of order %s was changed by another process", saga.OrderId))
+	}
+	saga.FencingToken = token
+
+	return nil
+}
+
+func (r *orderRepo) createItems(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, items []*models.OrderItem) error {
+	for _, item := range items {
+		if _, err := tx.ExecContext(
+			ctx,
+			createOrderItem,
+			orderID,
+			&item.ItemId,
+			&item.Cost,
+			&item.Qty,
+			&item.Sum,
+		); err != nil {
+			return errors.Wrap(err, "orderRepo.createItems.ExecContext")
+		}
+	}
+	return nil
+}
+
+// Append order status transition to the order history
+func (r *orderRepo) createStatusHistory(ctx context.Context, tx *sqlx.Tx, history *models.OrderStatusHistory) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		createStatusHistory,
+		&history.OrderId,
+		&history.FromStatus,
+		&history.ToStatus,
+		&history.Actor,
+		history.ActorID,
+		&history.Reason,
+	); err != nil {
+		return errors.Wrap(err, "orderRepo.createStatusHistory.ExecContext")
+	}
+	return nil
+}
+
+// Load items of several orders with a single query
+func (r *orderRepo) fillItems(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*models.Order, len(orders))
+	placeholders := make([]string, 0, len(orders))
+	args := make([]interface{}, 0, len(orders))
+	for i, o := range orders {
+		byID[o.OrderId] = o
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, o.OrderId)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, fmt.Sprintf(getItemsByOrderIDs, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.fillItems.QueryxContext")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &orderItemRow{}
+		if err = rows.StructScan(item); err != nil {
+			return errors.Wrap(err, "orderRepo.fillItems.StructScan")
+		}
+		if o, ok := byID[item.OrderId]; ok {
+			o.OrderList = append(o.OrderList, &item.OrderItem)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return errors.Wrap(err, "orderRepo.fillItems.rows.Err")
+	}
+
+	return nil
+}
+
+// Order item joined with its order id
+type orderItemRow struct {
+	OrderId uuid.UUID `db:"order_id"`
+	models.OrderItem
+}
+
+// Build WHERE clause with positional arguments for the order list filter
+func buildOrderFilter(filter *models.OrderFilter) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Status != models.OrderStatusUndefined {
+		add("status = $%d", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at <= $%d", *filter.CreatedTo)
+	}
+	if filter.CreatedBefore != nil {
+		add("created_at < $%d", *filter.CreatedBefore)
+	}
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.MinSum > 0 {
+		add("sum >= $%d", filter.MinSum)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
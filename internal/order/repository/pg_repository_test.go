@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+)
+
+func TestOrderRepo_Create(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	t.Run("Create", func(t *testing.T) {
+		orderID := uuid.New()
+		item := &models.OrderItem{ItemId: uuid.New(), Cost: 10, Qty: 2, Sum: 20}
+		order := &models.Order{
+			OrderId:       orderID,
+			Status:        models.OrderStatusCreated,
+			StatusMessage: models.OrderStatusCreated.ToString(),
+			Sum:           20,
+			OrderList:     []*models.OrderItem{item},
+		}
+		history := &models.OrderStatusHistory{OrderId: orderID, ToStatus: order.Status, Actor: models.OrderStatusActorUser}
+
+		rows := sqlmock.NewRows([]string{"order_id", "status", "status_message", "sum"}).
+			AddRow(orderID, order.Status, order.StatusMessage, order.Sum)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(createOrder).WithArgs(orderID, order.UserID, order.ReservationID, &order.Status, &order.StatusMessage, &order.Sum).WillReturnRows(rows)
+		mock.ExpectExec(createOrderItem).WithArgs(orderID, &item.ItemId, &item.Cost, &item.Qty, &item.Sum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(createStatusHistory).WithArgs(&history.OrderId, &history.FromStatus, &history.ToStatus, &history.Actor, history.ActorID, &history.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		createdOrder, err := orderRepo.Create(context.Background(), order, history)
+		require.NoError(t, err)
+		require.NotNil(t, createdOrder)
+		require.Equal(t, orderID, createdOrder.OrderId)
+		require.Len(t, createdOrder.OrderList, 1)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_GetOrderByID(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	t.Run("GetOrderByID", func(t *testing.T) {
+		orderID := uuid.New()
+		itemID := uuid.New()
+
+		orderRows := sqlmock.NewRows([]string{"order_id", "status", "status_message", "sum"}).
+			AddRow(orderID, models.OrderStatusConfirmed, models.OrderStatusConfirmed.ToString(), 30)
+		itemRows := sqlmock.NewRows([]string{"item_id", "cost", "qty", "sum"}).
+			AddRow(itemID, 10, 3, 30)
+
+		mock.ExpectQuery(getOrderByID).WithArgs(orderID).WillReturnRows(orderRows)
+		mock.ExpectQuery(getOrderItems).WithArgs(orderID).WillReturnRows(itemRows)
+
+		order, err := orderRepo.GetOrderByID(context.Background(), orderID)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusConfirmed, order.Status)
+		require.Len(t, order.OrderList, 1)
+		require.Equal(t, itemID, order.OrderList[0].ItemId)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_Delete(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	t.Run("Delete", func(t *testing.T) {
+		orderID := uuid.New()
+		mock.ExpectExec(deleteOrder).WithArgs(orderID, int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := orderRepo.Delete(context.Background(), orderID, 3)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("StaleToken", func(t *testing.T) {
+		orderID := uuid.New()
+		mock.ExpectExec(deleteOrder).WithArgs(orderID, int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := orderRepo.Delete(context.Background(), orderID, 2)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_GetStatusHistory(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	t.Run("GetStatusHistory", func(t *testing.T) {
+		orderID := uuid.New()
+		userID := uuid.New()
+
+		rows := sqlmock.NewRows([]string{"history_id", "order_id", "from_status", "to_status", "actor", "actor_id", "reason"}).
+			AddRow(uuid.New(), orderID, models.OrderStatusUndefined, models.OrderStatusCreated, models.OrderStatusActorUser, userID, "").
+			AddRow(uuid.New(), orderID, models.OrderStatusCreated, models.OrderStatusConfirmed, models.OrderStatusActorScheduler, nil, "Automatic order processing")
+
+		mock.ExpectQuery(getStatusHistory).WithArgs(orderID).WillReturnRows(rows)
+
+		history, err := orderRepo.GetStatusHistory(context.Background(), orderID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, userID, *history[0].ActorID)
+		require.Nil(t, history[1].ActorID)
+		require.Equal(t, models.OrderStatusActorScheduler, history[1].Actor)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_GetOrders(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	t.Run("CreatedBefore", func(t *testing.T) {
+		nextDay := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+		filter := &models.OrderFilter{CreatedBefore: &nextDay}
+		pq := &utils.PaginationQuery{Size: 10, Page: 1}
+
+		mock.ExpectQuery(getTotalCount + " WHERE created_at < $1").WithArgs(nextDay).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		orderList, err := orderRepo.GetOrders(context.Background(), filter, pq)
+		require.NoError(t, err)
+		require.Empty(t, orderList.Orders)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetOrders", func(t *testing.T) {
+		orderID := uuid.New()
+		userID := uuid.New()
+		itemID := uuid.New()
+		filter := &models.OrderFilter{Status: models.OrderStatusCreated, UserID: &userID}
+		pq := &utils.PaginationQuery{Size: 10, Page: 1}
+
+		totalCountRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+		orderRows := sqlmock.NewRows([]string{"order_id", "user_id", "status", "status_message", "sum"}).
+			AddRow(orderID, userID, models.OrderStatusCreated, models.OrderStatusCreated.ToString(), 30)
+		itemRows := sqlmock.NewRows([]string{"order_id", "item_id", "cost", "qty", "sum"}).
+			AddRow(orderID, itemID, 10, 3, 30)
+
+		where := " WHERE status = $1 AND user_id = $2"
+		mock.ExpectQuery(getTotalCount+where).WithArgs(filter.Status, userID).WillReturnRows(totalCountRows)
+		mock.ExpectQuery(getOrders+where+" ORDER BY created_at DESC OFFSET $3 LIMIT $4").
+			WithArgs(filter.Status, userID, 0, 10).WillReturnRows(orderRows)
+		mock.ExpectQuery(fmt.Sprintf(getItemsByOrderIDs, "$1")).WithArgs(orderID).WillReturnRows(itemRows)
+
+		orderList, err := orderRepo.GetOrders(context.Background(), filter, pq)
+		require.NoError(t, err)
+		require.Equal(t, 1, orderList.TotalCount)
+		require.False(t, orderList.HasMore)
+		require.Len(t, orderList.Orders, 1)
+		require.Equal(t, userID, *orderList.Orders[0].UserID)
+		require.Len(t, orderList.Orders[0].OrderList, 1)
+		require.Equal(t, itemID, orderList.Orders[0].OrderList[0].ItemId)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_UpdateFenced(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	orderID := uuid.New()
+	item := &models.OrderItem{ItemId: uuid.New(), Cost: 10, Qty: 2, Sum: 20}
+	order := &models.Order{
+		OrderId:       orderID,
+		Status:        models.OrderStatusConfirmed,
+		StatusMessage: models.OrderStatusConfirmed.ToString(),
+		Sum:           20,
+		OrderList:     []*models.OrderItem{item},
+	}
+	history := &models.OrderStatusHistory{
+		OrderId:    orderID,
+		FromStatus: models.OrderStatusCreated,
+		ToStatus:   order.Status,
+		Actor:      models.OrderStatusActorScheduler,
+	}
+
+	t.Run("UpdateFenced", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"order_id", "status", "status_message", "sum", "fencing_token"}).
+			AddRow(orderID, order.Status, order.StatusMessage, order.Sum, 2)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateOrderFenced).WithArgs(&order.Status, &order.StatusMessage, &order.Sum, &order.OrderId, int64(2), order.ReservationID).WillReturnRows(rows)
+		mock.ExpectExec(deleteOrderItems).WithArgs(orderID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(createOrderItem).WithArgs(orderID, &item.ItemId, &item.Cost, &item.Qty, &item.Sum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(createStatusHistory).WithArgs(&history.OrderId, &history.FromStatus, &history.ToStatus, &history.Actor, history.ActorID, &history.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		updatedOrder, err := orderRepo.UpdateFenced(context.Background(), order, history, 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updatedOrder.FencingToken)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("StaleToken", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateOrderFenced).WithArgs(&order.Status, &order.StatusMessage, &order.Sum, &order.OrderId, int64(1), order.ReservationID).
+			WillReturnRows(sqlmock.NewRows([]string{"order_id"}))
+		mock.ExpectRollback()
+
+		_, err := orderRepo.UpdateFenced(context.Background(), order, history, 1)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepo_IssueFencingToken(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+	orderID := uuid.New()
+
+	mock.ExpectQuery(issueFencingToken).WithArgs(orderID).WillReturnRows(sqlmock.NewRows([]string{"fencing_token"}).AddRow(8))
+
+	token, err := orderRepo.IssueFencingToken(context.Background(), orderID)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), token)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepo_SaveSaga(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	orderRepo := NewOrderRepository(sqlxDB)
+
+	saga := &models.OrderSaga{
+		OrderId: uuid.New(),
+		Step:    models.OrderSagaStepCharge,
+		State:   models.OrderSagaStateRunning,
+	}
+
+	t.Run("SaveSaga", func(t *testing.T) {
+		mock.ExpectExec(saveOrderSaga).
+			WithArgs(&saga.OrderId, &saga.Step, &saga.State, saga.ReservationID, saga.PaymentID, saga.ShipmentID, &saga.Failure, &saga.CancelActor, saga.CancelActorID, &saga.CancelReason, int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, orderRepo.SaveSaga(context.Background(), saga, 3))
+		require.Equal(t, int64(3), saga.FencingToken)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("StaleToken", func(t *testing.T) {
+		mock.ExpectExec(saveOrderSaga).
+			WithArgs(&saga.OrderId, &saga.Step, &saga.State, saga.ReservationID, saga.PaymentID, saga.ShipmentID, &saga.Failure, &saga.CancelActor, saga.CancelActorID, &saga.CancelReason, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := orderRepo.SaveSaga(context.Background(), saga, 2)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// Order keys iterator walking the whole SCAN cursor
+type scanIterator struct {
+	redisClient *redis.Client
+	batchSize   int64
+	cursor      uint64
+	started     bool
+	keys        []string
+	key         string
+	err         error
+}
+
+// Advance to next key, loads next batch when current one is used up
+func (it *scanIterator) Next(ctx context.Context) bool {
+	for len(it.keys) == 0 {
+		if it.err != nil || (it.started && it.cursor == 0) {
+			return false
+		}
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		it.load(ctx)
+	}
+	it.key, it.keys = it.keys[0], it.keys[1:]
+	return true
+}
+
+func (it *scanIterator) load(ctx context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "scanIterator.load")
+	defer span.Finish()
+
+	keys, cursor, err := it.redisClient.Scan(ctx, it.cursor, basePrefix+"*", it.batchSize).Result()
+	if err != nil {
+		it.err = errors.Wrap(err, "scanIterator.load.redisClient.Scan")
+		return
+	}
+	it.started = true
+	it.cursor = cursor
+	it.keys = keys
+}
+
+func (it *scanIterator) Key() string {
+	return it.key
+}
+
+func (it *scanIterator) Err() error {
+	return it.err
+}
+
+// Order keys iterator over creation time index, pages by rank
+type indexIterator struct {
+	redisClient *redis.Client
+	batchSize   int64
+	offset      int64
+	done        bool
+	keys        []string
+	key         string
+	err         error
+}
+
+// Advance to next key, loads next batch when current one is used up
+func (it *indexIterator) Next(ctx context.Context) bool {
+	for len(it.keys) == 0 {
+		if it.err != nil || it.done {
+			return false
+		}
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		it.load(ctx)
+	}
+	it.key, it.keys = it.keys[0], it.keys[1:]
+	return true
+}
+
+// Load next page of index, keys whose cache entry expired are removed from index
+func (it *indexIterator) load(ctx context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "indexIterator.load")
+	defer span.Finish()
+
+	batchSize := it.batchSize
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	keys, err := it.redisClient.ZRange(ctx, indexKey, it.offset, it.offset+batchSize-1).Result()
+	if err != nil {
+		it.err = errors.Wrap(err, "indexIterator.load.redisClient.ZRange")
+		return
+	}
+	if int64(len(keys)) < batchSize {
+		it.done = true
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	pipe := it.redisClient.Pipeline()
+	exists := make([]*redis.IntCmd, 0, len(keys))
+	for _, key := range keys {
+		exists = append(exists, pipe.Exists(ctx, key))
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		it.err = errors.Wrap(err, "indexIterator.load.pipe.Exec")
+		return
+	}
+
+	expired := make([]interface{}, 0)
+	for i, key := range keys {
+		if exists[i].Val() == 0 {
+			expired = append(expired, key)
+			continue
+		}
+		it.keys = append(it.keys, key)
+	}
+	if len(expired) > 0 {
+		if err = it.redisClient.ZRem(ctx, indexKey, expired...).Err(); err != nil {
+			it.err = errors.Wrap(err, "indexIterator.load.redisClient.ZRem")
+			return
+		}
+	}
+	// Removed keys shift ranks of the rest of index
+	it.offset += int64(len(keys) - len(expired))
+}
+
+func (it *indexIterator) Key() string {
+	return it.key
+}
+
+func (it *indexIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Redis variables
+const lockPrefix = "api-orders-lock:"
+
+const defaultLockTTL = 30 * time.Second
+
+// Prolong lock while it is held by owner
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Release lock while it is held by owner, lock of next owner is kept
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Order locker on redis, held lock expires after ttl unless renewed.
+// Fencing tokens are issued by order repository, so they survive loss of redis data
+type orderLocker struct {
+	redisClient *redis.Client
+	orderRepo   order.Repository
+	ttl         time.Duration
+}
+
+// Order locker constructor
+func NewOrderLocker(redisClient *redis.Client, orderRepo order.Repository, ttl time.Duration) order.Locker {
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+	return &orderLocker{redisClient: redisClient, orderRepo: orderRepo, ttl: ttl}
+}
+
+// Acquire lock of order and issue next fencing token, lock is renewed every third of ttl until it is released or lost
+func (l *orderLocker) Lock(ctx context.Context, orderID uuid.UUID) (order.Lock, context.Context, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderLocker.Lock")
+	defer span.Finish()
+
+	owner := uuid.New().String()
+	key := lockPrefix + orderID.String()
+	ok, err := l.redisClient.SetNX(ctx, key, owner, l.ttl).Result()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "orderLocker.Lock.redisClient.SetNX")
+	}
+	if !ok {
+		return nil, nil, errors.Wrapf(order.ErrLocked, "orderLocker.Lock %s", orderID)
+	}
+
+	token, err := l.orderRepo.IssueFencingToken(ctx, orderID)
+	if err != nil {
+		if relErr := releaseScript.Run(ctx, l.redisClient, []string{key}, owner).Err(); relErr != nil {
+			err = errors.WithMessagef(err, "release failed: %s", relErr)
+		}
+		return nil, nil, errors.Wrap(err, "orderLocker.Lock.IssueFencingToken")
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	lock := &redisLock{
+		redisClient: l.redisClient,
+		key:         key,
+		owner:       owner,
+		token:       token,
+		ttl:         l.ttl,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go lock.renew(lockCtx)
+	return lock, lockCtx, nil
+}
+
+// Lock held on redis
+type redisLock struct {
+	redisClient *redis.Client
+	key         string
+	owner       string
+	token       int64
+	ttl         time.Duration
+	cancel      context.CancelFunc
+	done        chan struct{}
+	once        sync.Once
+}
+
+// Fencing token, writes guarded by lock are accepted only with token not older than the last accepted one
+func (l *redisLock) Token() int64 {
+	return l.token
+}
+
+// Release lock, context of lock is cancelled
+func (l *redisLock) Unlock(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "redisLock.Unlock")
+	defer span.Finish()
+
+	l.once.Do(l.cancel)
+	<-l.done
+	if err := releaseScript.Run(ctx, l.redisClient, []string{l.key}, l.owner).Err(); err != nil {
+		return errors.Wrap(err, "redisLock.Unlock.releaseScript")
+	}
+	return nil
+}
+
+// Renew lock until context is done. Lock is lost when key changed owner or renewal failed for whole ttl
+func (l *redisLock) renew(ctx context.Context) {
+	defer close(l.done)
+	defer l.once.Do(l.cancel)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := renewScript.Run(ctx, l.redisClient, []string{l.key}, l.owner, l.ttl.Milliseconds()).Int64()
+			if err == nil && ok == 0 {
+				return
+			}
+			if err == nil {
+				renewed = time.Now()
+				continue
+			}
+			if time.Since(renewed) >= l.ttl {
+				return
+			}
+		}
+	}
+}
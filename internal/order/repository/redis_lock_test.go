@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/mock"
+)
+
+// Locker over miniredis issuing increasing fencing tokens from mocked orders repository
+func SetupLocker(t *testing.T, ttl time.Duration) (*miniredis.Miniredis, order.Locker) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	var token int64
+	mockOrderRepo.EXPECT().IssueFencingToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, orderID uuid.UUID) (int64, error) {
+		token++
+		return token, nil
+	}).AnyTimes()
+
+	return mr, NewOrderLocker(client, mockOrderRepo, ttl)
+}
+
+func TestOrderLocker_Lock(t *testing.T) {
+	t.Parallel()
+
+	mr, locker := SetupLocker(t, time.Minute)
+	defer mr.Close()
+
+	ctx := context.Background()
+	resource := uuid.New()
+
+	lock, lockCtx, err := locker.Lock(ctx, resource)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), lock.Token())
+
+	// Other replica can not take held lock
+	_, _, err = locker.Lock(ctx, resource)
+	require.ErrorIs(t, err, order.ErrLocked)
+
+	// Other resources are locked independently
+	other, _, err := locker.Lock(ctx, uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, other.Unlock(ctx))
+
+	require.NoError(t, lock.Unlock(ctx))
+	require.ErrorIs(t, lockCtx.Err(), context.Canceled)
+	require.False(t, mr.Exists(lockPrefix+resource.String()))
+
+	next, _, err := locker.Lock(ctx, resource)
+	require.NoError(t, err)
+	require.Greater(t, next.Token(), lock.Token())
+	require.NoError(t, next.Unlock(ctx))
+}
+
+func TestOrderLocker_Renew(t *testing.T) {
+	t.Parallel()
+
+	ttl := 300 * time.Millisecond
+	mr, locker := SetupLocker(t, ttl)
+	defer mr.Close()
+
+	ctx := context.Background()
+	resource := uuid.New()
+
+	lock, lockCtx, err := locker.Lock(ctx, resource)
+	require.NoError(t, err)
+	defer lock.Unlock(ctx)
+
+	mr.SetTTL(lockPrefix+resource.String(), 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return mr.TTL(lockPrefix+resource.String()) > 10*time.Millisecond
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, lockCtx.Err())
+}
+
+func TestOrderLocker_Lost(t *testing.T) {
+	t.Parallel()
+
+	ttl := 300 * time.Millisecond
+	mr, locker := SetupLocker(t, ttl)
+	defer mr.Close()
+
+	ctx := context.Background()
+	resource := uuid.New()
+
+	stale, staleCtx, err := locker.Lock(ctx, resource)
+	require.NoError(t, err)
+
+	// Lock expired while owner was stalled and next owner took it
+	mr.FastForward(ttl)
+	lock, _, err := locker.Lock(ctx, resource)
+	require.NoError(t, err)
+	require.Greater(t, lock.Token(), stale.Token())
+
+	// Stale owner notices the loss and does not release lock of next owner
+	select {
+	case <-staleCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("lost lock context is not cancelled")
+	}
+	require.NoError(t, stale.Unlock(ctx))
+	require.True(t, mr.Exists(lockPrefix+resource.String()))
+
+	_, _, err = locker.Lock(ctx, resource)
+	require.ErrorIs(t, err, order.ErrLocked)
+	require.NoError(t, lock.Unlock(ctx))
+}
+
+func TestOrderLocker_IssueFencingTokenFailed(t *testing.T) {
+	t.Parallel()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	ctrl := gomock.NewController(t)
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	locker := NewOrderLocker(client, mockOrderRepo, time.Minute)
+
+	ctx := context.Background()
+	resource := uuid.New()
+	mockOrderRepo.EXPECT().IssueFencingToken(gomock.Any(), resource).Return(int64(0), sql.ErrNoRows)
+
+	// Lock without fencing token is released at once
+	_, _, err = locker.Lock(ctx, resource)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.False(t, mr.Exists(lockPrefix+resource.String()))
+}
@@ -14,6 +14,7 @@ import (
 // Redis variables
 const (
 	basePrefix    = "api-orders:"
+	indexKey      = "api-orders-index"
 	cacheDuration = 3600
 )
 
@@ -27,16 +28,14 @@ func NewOrderRedisRepo(redisClient *redis.Client) order.RedisRepository {
 	return &orderRedisRepo{redisClient: redisClient}
 }
 
-func (n *orderRedisRepo) GetOrderKeysCtx(ctx context.Context) ([]string, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRedisRepo.GetOrderKeysCtx")
-	defer span.Finish()
-	var cursor uint64
+// Iterate cached order keys by creation time using index, expired keys are dropped from index on the way
+func (n *orderRedisRepo) OrderKeys(batchSize int64) order.KeyIterator {
+	return &indexIterator{redisClient: n.redisClient, batchSize: batchSize}
+}
 
-	keys, cursor, err := n.redisClient.Scan(ctx, cursor, basePrefix+"*", 0).Result()
-	if err != nil {
-		return nil, errors.Wrap(err, "orderRedisRepo.GetOrderKeysCtx.redisClient.Scan")
-	}
-	return keys, nil
+// Iterate cached order keys with keyspace scan, finds keys missing in index, a key may be returned more than once
+func (n *orderRedisRepo) ScanOrderKeys(batchSize int64) order.KeyIterator {
+	return &scanIterator{redisClient: n.redisClient, batchSize: batchSize}
 }
 
 // Get order by id
@@ -45,6 +44,9 @@ func (n *orderRedisRepo) GetOrderByIDCtx(ctx context.Context, key string) (*mode
 	defer span.Finish()
 
 	newsBytes, err := n.redisClient.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "orderRedisRepo.GetOrderByIDCtx.redisClient.Get")
 	}
@@ -65,8 +67,17 @@ func (n *orderRedisRepo) SetOrderCtx(ctx context.Context, key string, seconds in
 	if err != nil {
 		return errors.Wrap(err, "orderRedisRepo.SetOrderCtx.json.Marshal")
 	}
-	if err = n.redisClient.Set(ctx, key, orderBytes, time.Second*time.Duration(seconds)).Err(); err != nil {
-		return errors.Wrap(err, "orderRedisRepo.SetOrderCtx.redisClient.Set")
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err = n.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, orderBytes, time.Second*time.Duration(seconds))
+		pipe.ZAdd(ctx, indexKey, &redis.Z{Score: float64(createdAt.UnixMilli()), Member: key})
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "orderRedisRepo.SetOrderCtx.redisClient.TxPipelined")
 	}
 	return nil
 }
@@ -76,8 +87,36 @@ func (n *orderRedisRepo) DeleteOrderCtx(ctx context.Context, key string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRedisRepo.DeleteOrderCtx")
 	defer span.Finish()
 
+	_, err := n.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.ZRem(ctx, indexKey, key)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "orderRedisRepo.DeleteOrderCtx.redisClient.TxPipelined")
+	}
+	return nil
+}
+
+// Mark order task as queued, returns false when it is already marked
+func (n *orderRedisRepo) SetOrderTaskCtx(ctx context.Context, key string, seconds int) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRedisRepo.SetOrderTaskCtx")
+	defer span.Finish()
+
+	ok, err := n.redisClient.SetNX(ctx, key, 1, time.Second*time.Duration(seconds)).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "orderRedisRepo.SetOrderTaskCtx.redisClient.SetNX")
+	}
+	return ok, nil
+}
+
+// Drop queued mark of order task, so the task can be queued again
+func (n *orderRedisRepo) DeleteOrderTaskCtx(ctx context.Context, key string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRedisRepo.DeleteOrderTaskCtx")
+	defer span.Finish()
+
 	if err := n.redisClient.Del(ctx, key).Err(); err != nil {
-		return errors.Wrap(err, "orderRedisRepo.DeleteOrderCtx.redisClient.Del")
+		return errors.Wrap(err, "orderRedisRepo.DeleteOrderTaskCtx.redisClient.Del")
 	}
 	return nil
 }
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+)
+
+func SetupRedis() (*miniredis.Miniredis, order.RedisRepository) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, NewOrderRedisRepo(client)
+}
+
+func collectKeys(t *testing.T, it order.KeyIterator) []string {
+	keys := make([]string, 0)
+	for it.Next(context.Background()) {
+		keys = append(keys, it.Key())
+	}
+	require.NoError(t, it.Err())
+	return keys
+}
+
+// Cache five orders created a second apart, the second one expires in ten seconds
+func setupOrderKeys(t *testing.T) (*miniredis.Miniredis, order.RedisRepository, []string) {
+	mr, orderRedisRepo := SetupRedis()
+	t.Cleanup(mr.Close)
+
+	createdAt := time.Now()
+	keys := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		key := basePrefix + uuid.New().String()
+		seconds := cacheDuration
+		if i == 1 {
+			seconds = 10
+		}
+		err := orderRedisRepo.SetOrderCtx(context.Background(), key, seconds, &models.Order{CreatedAt: createdAt.Add(time.Duration(i) * time.Second)})
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	return mr, orderRedisRepo, keys
+}
+
+func TestOrderRedisRepo_OrderKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreationOrder", func(t *testing.T) {
+		_, orderRedisRepo, keys := setupOrderKeys(t)
+
+		require.Equal(t, keys, collectKeys(t, orderRedisRepo.OrderKeys(2)))
+	})
+
+	t.Run("DeletedAndExpired", func(t *testing.T) {
+		mr, orderRedisRepo, keys := setupOrderKeys(t)
+
+		require.NoError(t, orderRedisRepo.DeleteOrderCtx(context.Background(), keys[3]))
+		mr.FastForward(time.Minute)
+
+		require.Equal(t, []string{keys[0], keys[2], keys[4]}, collectKeys(t, orderRedisRepo.OrderKeys(2)))
+
+		members, err := mr.ZMembers(indexKey)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{keys[0], keys[2], keys[4]}, members)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		_, orderRedisRepo, _ := setupOrderKeys(t)
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		it := orderRedisRepo.OrderKeys(2)
+		require.False(t, it.Next(cancelled))
+		require.ErrorIs(t, it.Err(), context.Canceled)
+	})
+}
+
+func TestOrderRedisRepo_ScanOrderKeys(t *testing.T) {
+	t.Parallel()
+
+	mr, orderRedisRepo := SetupRedis()
+	defer mr.Close()
+
+	ctx := context.Background()
+	keys := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		key := basePrefix + uuid.New().String()
+		require.NoError(t, orderRedisRepo.SetOrderCtx(ctx, key, cacheDuration, &models.Order{}))
+		keys = append(keys, key)
+	}
+	// Keys outside of order cache are not listed
+	require.NoError(t, mr.Set("api-orders-task:"+uuid.New().String(), "1"))
+
+	require.ElementsMatch(t, keys, collectKeys(t, orderRedisRepo.ScanOrderKeys(1)))
+}
@@ -0,0 +1,111 @@
+package repository
+
+const (
+	createOrder = `INSERT INTO orders (order_id, user_id, reservation_id, status, status_message, sum, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, now())
+						RETURNING *`
+	updateOrder = `UPDATE orders
+						SET status = $1,
+							status_message = $2,
+							sum = $3,
+							updated_at = now()
+						WHERE order_id = $4
+						RETURNING *`
+	updateOrderFenced = `UPDATE orders
+						SET status = $1,
+							status_message = $2,
+							sum = $3,
+							reservation_id = $6,
+							updated_at = now()
+						WHERE order_id = $4 AND fencing_token = $5
+						RETURNING *`
+	issueFencingToken = `UPDATE orders SET fencing_token = fencing_token + 1 WHERE order_id = $1 RETURNING fencing_token`
+	getOrderByID      = `SELECT order_id,
+						user_id,
+						reservation_id,
+						status,
+						status_message,
+						sum,
+						created_at,
+						updated_at
+					FROM orders
+					WHERE order_id = $1`
+	deleteOrder         = `DELETE FROM orders WHERE order_id = $1 AND fencing_token = $2`
+	getActiveOrderTasks = `SELECT order_id, status
+					FROM orders
+					WHERE status NOT IN ($1, $2)
+					ORDER BY created_at`
+
+	getTotalCount = `SELECT COUNT(order_id) FROM orders`
+	getOrders     = `SELECT order_id,
+						user_id,
+						reservation_id,
+						status,
+						status_message,
+						sum,
+						created_at,
+						updated_at
+					FROM orders`
+
+	createOrderItem = `INSERT INTO order_items (order_id, item_id, cost, qty, sum)
+						VALUES ($1, $2, $3, $4, $5)`
+	deleteOrderItems = `DELETE FROM order_items WHERE order_id = $1`
+	getOrderItems    = `SELECT item_id,
+						cost,
+						qty,
+						sum
+					FROM order_items
+					WHERE order_id = $1`
+	getItemsByOrderIDs = `SELECT order_id,
+						item_id,
+						cost,
+						qty,
+						sum
+					FROM order_items
+					WHERE order_id IN (%s)`
+
+	createStatusHistory = `INSERT INTO order_status_history (order_id, from_status, to_status, actor, actor_id, reason, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, now())`
+	getStatusHistory = `SELECT history_id,
+						order_id,
+						from_status,
+						to_status,
+						actor,
+						actor_id,
+						reason,
+						created_at
+					FROM order_status_history
+					WHERE order_id = $1
+					ORDER BY created_at`
+
+	getOrderSaga = `SELECT order_id,
+						step,
+						state,
+						reservation_id,
+						payment_id,
+						shipment_id,
+						failure,
+						cancel_actor,
+						cancel_actor_id,
+						cancel_reason,
+						fencing_token,
+						created_at,
+						updated_at
+					FROM order_sagas
+					WHERE order_id = $1`
+	saveOrderSaga = `INSERT INTO order_sagas (order_id, step, state, reservation_id, payment_id, shipment_id, failure, cancel_actor, cancel_actor_id, cancel_reason, fencing_token, created_at, updated_at)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
+						ON CONFLICT (order_id) DO UPDATE
+						SET step = EXCLUDED.step,
+							state = EXCLUDED.state,
+							reservation_id = EXCLUDED.reservation_id,
+							payment_id = EXCLUDED.payment_id,
+							shipment_id = EXCLUDED.shipment_id,
+							failure = EXCLUDED.failure,
+							cancel_actor = EXCLUDED.cancel_actor,
+							cancel_actor_id = EXCLUDED.cancel_actor_id,
+							cancel_reason = EXCLUDED.cancel_reason,
+							fencing_token = EXCLUDED.fencing_token,
+							updated_at = now()
+						WHERE order_sagas.fencing_token <= EXCLUDED.fencing_token`
+)
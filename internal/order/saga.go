@@ -0,0 +1,17 @@
+//go:generate mockgen -source saga.go -destination mock/saga_mock.go -package mock
+package order
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+)
+
+// Order fulfilment saga: reserve stock, charge, package and ship, failed saga is compensated in reverse order
+type Saga interface {
+	// Run steps of order current stage, or compensate saga after failed step and cancel the order.
+	// Order status is changed in place, returned error means saga resumes later from persisted state
+	Run(ctx context.Context, order *models.Order, token int64) (*models.OrderSaga, error)
+	// Mark saga of order compensating on behalf of cancellation actor, next run rolls back completed steps
+	// and cancels the order. Actor and reason of cancellation are kept with saga for order status history
+	Cancel(ctx context.Context, order *models.Order, cancellation *models.OrderStatusHistory, token int64) (*models.OrderSaga, error)
+}
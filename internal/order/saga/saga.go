@@ -0,0 +1,539 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+const (
+	// Stock reserved by saga is held while order is charged and packaged, reservation lost by a crash expires
+	reservationTTL    = 15 * time.Minute
+	grpcTimeout       = 5 * time.Second
+	movementsPageSize = 100
+)
+
+// Business failure of step, saga is compensated instead of retried
+type stepFailure struct {
+	reason string
+}
+
+func (f *stepFailure) Error() string {
+	return f.reason
+}
+
+// Saga step with its compensation. Step runs while order is in stage,
+// next is order status after the step, undefined keeps the status
+type sagaStep struct {
+	stage      models.OrderStatus
+	next       models.OrderStatus
+	do         func(ctx context.Context, value *models.Order, saga *models.OrderSaga) error
+	compensate func(ctx context.Context, value *models.Order, saga *models.OrderSaga) error
+}
+
+// Orchestrated order fulfilment saga, state is persisted after every step
+type orderSaga struct {
+	cfg          *config.Config
+	orderRepo    order.Repository
+	stateMachine order.StateMachine
+	grpcClient   pb.InventoryServiceClient
+	payment      order.PaymentGateway
+	shipping     order.ShippingGateway
+	logger       logger.Logger
+	steps        []sagaStep
+}
+
+// Order saga constructor
+func NewOrderSaga(cfg *config.Config, orderRepo order.Repository, stateMachine order.StateMachine, grpcClient pb.InventoryServiceClient, payment order.PaymentGateway, shipping order.ShippingGateway, logger logger.Logger) order.Saga {
+	s := &orderSaga{cfg: cfg, orderRepo: orderRepo, stateMachine: stateMachine, grpcClient: grpcClient, payment: payment, shipping: shipping, logger: logger}
+	s.steps = []sagaStep{
+		models.OrderSagaStepReserve: {stage: models.OrderStatusConfirmed, do: s.reserve, compensate: s.release},
+		models.OrderSagaStepCharge:  {stage: models.OrderStatusConfirmed, do: s.charge, compensate: s.refund},
+		models.OrderSagaStepPackage: {stage: models.OrderStatusConfirmed, next: models.OrderStatusPackaged, do: s.pack, compensate: s.restock},
+		models.OrderSagaStepShip:    {stage: models.OrderStatusPackaged, next: models.OrderStatusInDelivery, do: s.ship, compensate: s.cancelShipment},
+	}
+	return s
+}
+
+// Resume saga of order from persisted state
+func (s *orderSaga) Run(ctx context.Context, value *models.Order, token int64) (*models.OrderSaga, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderSaga.Run")
+	defer span.Finish()
+
+	saga, err := s.orderRepo.GetSaga(ctx, value.OrderId)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderSaga.Run.GetSaga")
+	}
+	if saga == nil {
+		if saga, err = s.start(value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch saga.State {
+	case models.OrderSagaStateRunning:
+		err = s.forward(ctx, value, saga, token)
+	case models.OrderSagaStateCompensating:
+		err = s.compensate(ctx, value, saga, token)
+	case models.OrderSagaStateCompleted:
+		_, err = s.pendingTransition(value, saga)
+	case models.OrderSagaStateCompensated:
+		err = s.cancel(value)
+	}
+	return saga, err
+}
+
+// Mark saga compensating on cancellation of order. Saga not started yet takes over reservation
+// made when order was created, so compensation releases it
+func (s *orderSaga) Cancel(ctx context.Context, value *models.Order, cancellation *models.OrderStatusHistory, token int64) (*models.OrderSaga, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderSaga.Cancel")
+	defer span.Finish()
+
+	saga, err := s.orderRepo.GetSaga(ctx, value.OrderId)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderSaga.Cancel.GetSaga")
+	}
+	if saga == nil {
+		if saga, err = s.start(value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch saga.State {
+	case models.OrderSagaStateCompensating, models.OrderSagaStateCompensated:
+		return saga, nil
+	case models.OrderSagaStateCompleted:
+		return nil, errors.Errorf("orderSaga.Cancel: saga of order %s is completed", value.OrderId)
+	}
+
+	if saga.ReservationID == nil && value.ReservationID != nil {
+		saga.ReservationID = value.ReservationID
+		if saga.Step == models.OrderSagaStepReserve {
+			saga.Step = models.OrderSagaStepCharge
+		}
+	}
+	saga.State = models.OrderSagaStateCompensating
+	saga.Failure = fmt.Sprintf("Cancelled by %s", cancellation.Actor)
+	saga.CancelActor = cancellation.Actor
+	saga.CancelActorID = cancellation.ActorID
+	saga.CancelReason = cancellation.Reason
+	if err = s.save(ctx, saga, token); err != nil {
+		return nil, err
+	}
+	return saga, nil
+}
+
+// New saga starts from the first step of order stage, so orders processed before saga are picked up midway
+func (s *orderSaga) start(value *models.Order) (*models.OrderSaga, error) {
+	for step, sagaStep := range s.steps {
+		if sagaStep.stage == value.Status {
+			return &models.OrderSaga{
+				OrderId: value.OrderId,
+				Step:    models.OrderSagaStep(step),
+				State:   models.OrderSagaStateRunning,
+			}, nil
+		}
+	}
+	return nil, errors.Errorf("orderSaga.start: no saga steps for order in status %s", value.Status.ToString())
+}
+
+// Run steps of order stage until a step changes order status
+func (s *orderSaga) forward(ctx context.Context, value *models.Order, saga *models.OrderSaga, token int64) error {
+	if changed, err := s.pendingTransition(value, saga); changed || err != nil {
+		return err
+	}
+
+	// Reservation is held until packaging commits it, however long charging is retried
+	if saga.ReservationID != nil && saga.Step > models.OrderSagaStepReserve && saga.Step <= models.OrderSagaStepPackage {
+		if err := s.extendReservation(ctx, value, saga); err != nil {
+			return errors.Wrap(err, "orderSaga.extendReservation")
+		}
+	}
+
+	for saga.Step < models.OrderSagaStepDone {
+		step := s.steps[saga.Step]
+		if step.stage != value.Status {
+			return nil
+		}
+
+		if err := step.do(ctx, value, saga); err != nil {
+			var failure *stepFailure
+			if !errors.As(err, &failure) {
+				return errors.Wrapf(err, "orderSaga.%s", saga.Step.ToString())
+			}
+			s.logger.Infof("Order %s saga %s step failed, compensating: %s", value.OrderId, saga.Step.ToString(), failure.reason)
+			saga.State = models.OrderSagaStateCompensating
+			saga.Failure = failure.reason
+			if err = s.save(ctx, saga, token); err != nil {
+				return err
+			}
+			return s.compensate(ctx, value, saga, token)
+		}
+
+		saga.Step++
+		if saga.Step == models.OrderSagaStepDone {
+			saga.State = models.OrderSagaStateCompleted
+		}
+		if err := s.save(ctx, saga, token); err != nil {
+			return err
+		}
+		if step.next != models.OrderStatusUndefined {
+			return s.stateMachine.Transition(value, step.next)
+		}
+	}
+	return nil
+}
+
+// Compensate completed steps in reverse order, then cancel the order
+func (s *orderSaga) compensate(ctx context.Context, value *models.Order, saga *models.OrderSaga, token int64) error {
+	for saga.Step > models.OrderSagaStepReserve {
+		step := saga.Step - 1
+		if err := s.steps[step].compensate(ctx, value, saga); err != nil {
+			return errors.Wrapf(err, "orderSaga.compensate.%s", step.ToString())
+		}
+		saga.Step = step
+		if err := s.save(ctx, saga, token); err != nil {
+			return err
+		}
+	}
+
+	saga.State = models.OrderSagaStateCompensated
+	if err := s.save(ctx, saga, token); err != nil {
+		return err
+	}
+	s.logger.Infof("Order %s saga compensated", value.OrderId)
+	return s.cancel(value)
+}
+
+// Status change of the last completed step is lost when order update failed after saga was saved
+func (s *orderSaga) pendingTransition(value *models.Order, saga *models.OrderSaga) (bool, error) {
+	if saga.Step == models.OrderSagaStepReserve {
+		return false, nil
+	}
+	last := s.steps[saga.Step-1]
+	if last.next == models.OrderStatusUndefined || value.Status != last.stage {
+		return false, nil
+	}
+	return true, s.stateMachine.Transition(value, last.next)
+}
+
+func (s *orderSaga) cancel(value *models.Order) error {
+	if value.Status == models.OrderStatusCancelled {
+		return nil
+	}
+	return s.stateMachine.Transition(value, models.OrderStatusCancelled)
+}
+
+func (s *orderSaga) save(ctx context.Context, saga *models.OrderSaga, token int64) error {
+	if err := s.orderRepo.SaveSaga(ctx, saga, token); err != nil {
+		return errors.Wrap(err, "orderSaga.SaveSaga")
+	}
+	return nil
+}
+
+// Hold order items, stock reserved when order was created is taken over
+func (s *orderSaga) reserve(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	if value.ReservationID != nil {
+		saga.ReservationID = value.ReservationID
+		return s.extendReservation(ctx, value, saga)
+	}
+
+	c, cancel := context.WithTimeout(ctx, grpcTimeout)
+	defer cancel()
+	resp, err := s.grpcClient.Reserve(c, &pb.ReserveRequest{
+		OrderId:    value.OrderId.String(),
+		Item:       orderItems(value),
+		TtlSeconds: uint64(reservationTTL.Seconds()),
+	})
+	if err != nil {
+		return rejectedError(err)
+	}
+	switch resp.Status {
+	case pb.Status_OK:
+		reservationID, err := uuid.Parse(resp.ReservationId)
+		if err != nil {
+			return errors.Wrap(err, "uuid.Parse")
+		}
+		saga.ReservationID = &reservationID
+		return nil
+	case pb.Status_NotEnoughAvailable:
+		return &stepFailure{reason: "Inventory not have items for order"}
+	default:
+		return errors.Errorf("reserve %s %s", resp.Status, resp.StatusMessage)
+	}
+}
+
+// Hold reserved stock for another reservation ttl. Expired reservation is not renewed,
+// packaging then allocates stock which is still available
+func (s *orderSaga) extendReservation(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	c, cancel := context.WithTimeout(ctx, grpcTimeout)
+	defer cancel()
+	_, err := s.grpcClient.ExtendReservation(c, &pb.ExtendReservationRequest{
+		ReservationId: saga.ReservationID.String(),
+		OrderId:       value.OrderId.String(),
+		TtlSeconds:    uint64(reservationTTL.Seconds()),
+	})
+	if status.Code(err) == codes.NotFound {
+		s.logger.Infof("Order %v reservation %s expired", value.OrderId, saga.ReservationID)
+		return nil
+	}
+	return err
+}
+
+// Give held stock back, reservation may be already committed or expired
+func (s *orderSaga) release(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	if saga.ReservationID == nil {
+		return nil
+	}
+
+	c, cancel := context.WithTimeout(ctx, grpcTimeout)
+	defer cancel()
+	resp, err := s.grpcClient.ReleaseReservation(c, &pb.ReservationRequest{
+		ReservationId: saga.ReservationID.String(),
+		OrderId:       value.OrderId.String(),
+		Actor:         string(models.OrderStatusActorScheduler),
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if resp.Status != pb.Status_OK && resp.Status != pb.Status_NotFound {
+		return errors.Errorf("release %s %s", resp.Status, resp.StatusMessage)
+	}
+	return nil
+}
+
+func (s *orderSaga) charge(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	paymentID, err := s.payment.Charge(ctx, value)
+	if errors.Is(err, order.ErrDeclined) {
+		return &stepFailure{reason: "Payment declined"}
+	}
+	if err != nil {
+		return err
+	}
+	saga.PaymentID = &paymentID
+	return nil
+}
+
+func (s *orderSaga) refund(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	if saga.PaymentID == nil {
+		return nil
+	}
+	err := s.payment.Refund(ctx, value, *saga.PaymentID)
+	if errors.Is(err, order.ErrDeclined) {
+		// Retrying does not help, refund is left to support
+		s.logger.Errorf("[SAGA]: Order %s refund of payment %s declined: %s", value.OrderId, *saga.PaymentID, err)
+		return nil
+	}
+	return err
+}
+
+// Take order items out of stock. Reserved stock is committed, missing or
+// no longer coverable reservation falls back to allocation
+func (s *orderSaga) pack(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	c, cancel := context.WithTimeout(ctx, grpcTimeout)
+	defer cancel()
+
+	// Stock taken out before a crash is not taken twice
+	taken, err := s.takenStock(c, value.OrderId)
+	if err != nil {
+		return err
+	}
+	if len(taken) > 0 {
+		return nil
+	}
+
+	strategy, location := s.allocation()
+	if saga.ReservationID != nil {
+		commitResp, err := s.grpcClient.CommitReservation(c, &pb.ReservationRequest{
+			ReservationId: saga.ReservationID.String(),
+			OrderId:       value.OrderId.String(),
+			Strategy:      strategy,
+			Location:      location,
+			Actor:         string(models.OrderStatusActorScheduler),
+		})
+		if err == nil && commitResp.Status == pb.Status_OK {
+			s.logger.Infof("Order %v reserved items committed", value.OrderId)
+			return nil
+		}
+		switch code := status.Code(err); code {
+		case codes.OK:
+			s.logger.Infof("Order %v reservation %s, allocating inventory", value.OrderId, commitResp.StatusMessage)
+		case codes.NotFound, codes.FailedPrecondition:
+			s.logger.Infof("Order %v reservation %s, allocating inventory", value.OrderId, status.Convert(err).Message())
+		default:
+			return errors.Wrap(err, "reservation commit")
+		}
+	}
+
+	resp, err := s.grpcClient.Allocate(c, &pb.AllocateRequest{Item: orderItems(value), Strategy: strategy, Location: location})
+	if err != nil {
+		return errors.Wrap(rejectedError(err), "allocation")
+	}
+	if resp.Status != pb.Status_OK {
+		return &stepFailure{reason: "Inventory not have items for order"}
+	}
+
+	removeResp, err := s.grpcClient.RemoveItem(c, &pb.ItemRequest{
+		Item:    resp.Allocations,
+		Reason:  pb.MovementReason_ReasonOrder,
+		OrderId: value.OrderId.String(),
+		Actor:   string(models.OrderStatusActorScheduler),
+	})
+	if err != nil {
+		return errors.Wrap(err, "items remove")
+	}
+	switch removeResp.Status {
+	case pb.Status_OK:
+		s.logger.Infof("Order %v fully packaged", value.OrderId)
+		return nil
+	case pb.Status_NotEnoughAvailable:
+		return &stepFailure{reason: "Inventory not have items for order"}
+	default:
+		return errors.Errorf("items remove %s %s", removeResp.Status, removeResp.StatusMessage)
+	}
+}
+
+// Return stock taken out for order to the warehouses it was taken from
+func (s *orderSaga) restock(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	c, cancel := context.WithTimeout(ctx, grpcTimeout)
+	defer cancel()
+
+	taken, err := s.takenStock(c, value.OrderId)
+	if err != nil {
+		return err
+	}
+	if len(taken) == 0 {
+		return nil
+	}
+
+	resp, err := s.grpcClient.AddItem(c, &pb.ItemRequest{
+		Item:    taken,
+		Reason:  pb.MovementReason_ReasonReturn,
+		OrderId: value.OrderId.String(),
+		Actor:   string(models.OrderStatusActorScheduler),
+	})
+	if err != nil {
+		return errors.Wrap(err, "items return")
+	}
+	if resp.Status != pb.Status_OK {
+		return errors.Errorf("items return %s %s", resp.Status, resp.StatusMessage)
+	}
+	s.logger.Infof("Order %v items returned to stock", value.OrderId)
+	return nil
+}
+
+func (s *orderSaga) ship(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	shipmentID, err := s.shipping.Ship(ctx, value)
+	if errors.Is(err, order.ErrDeclined) {
+		return &stepFailure{reason: "Shipment declined"}
+	}
+	if err != nil {
+		return err
+	}
+	saga.ShipmentID = &shipmentID
+	return nil
+}
+
+func (s *orderSaga) cancelShipment(ctx context.Context, value *models.Order, saga *models.OrderSaga) error {
+	if saga.ShipmentID == nil {
+		return nil
+	}
+	err := s.shipping.CancelShipment(ctx, value, *saga.ShipmentID)
+	if errors.Is(err, order.ErrDeclined) {
+		// Parcel already left, it is returned by delivery provider
+		s.logger.Errorf("[SAGA]: Order %s shipment %s cancel declined: %s", value.OrderId, *saga.ShipmentID, err)
+		return nil
+	}
+	return err
+}
+
+// Per warehouse quantities taken out of stock for order and not returned yet, rebuilt from inventory ledger
+func (s *orderSaga) takenStock(ctx context.Context, orderID uuid.UUID) ([]*pb.Item, error) {
+	type stockKey struct {
+		itemID      string
+		warehouseID string
+	}
+	net := make(map[stockKey]int64)
+	keys := make([]stockKey, 0)
+	for page := uint32(1); ; page++ {
+		resp, err := s.grpcClient.ListMovements(ctx, &pb.MovementListRequest{
+			OrderId: orderID.String(),
+			Page:    page,
+			Size:    movementsPageSize,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "movements list")
+		}
+		for _, movement := range resp.Movements {
+			key := stockKey{itemID: movement.ItemId, warehouseID: movement.WarehouseId}
+			if _, ok := net[key]; !ok {
+				keys = append(keys, key)
+			}
+			net[key] += movement.Delta
+		}
+		if !resp.HasMore {
+			break
+		}
+	}
+
+	items := make([]*pb.Item, 0)
+	for _, key := range keys {
+		if net[key] < 0 {
+			items = append(items, &pb.Item{Uuid: key.itemID, WarehouseId: key.warehouseID, Qty: uint64(-net[key])})
+		}
+	}
+	return items, nil
+}
+
+// Allocation strategy and delivery point from config
+func (s *orderSaga) allocation() (pb.AllocationStrategy, *pb.Location) {
+	strategy, err := models.ParseAllocationStrategy(s.cfg.Allocation.Strategy)
+	if err != nil {
+		strategy = models.AllocationStrategyMostStock
+		s.logger.Errorf("[SAGA]: %s %q, using %s", err, s.cfg.Allocation.Strategy, strategy)
+	}
+	if strategy == models.AllocationStrategyNearest {
+		return pb.AllocationStrategy_Nearest, &pb.Location{
+			Latitude:  s.cfg.Allocation.Latitude,
+			Longitude: s.cfg.Allocation.Longitude,
+		}
+	}
+	return pb.AllocationStrategy_MostStock, nil
+}
+
+// Order items with quantities of repeated items merged
+func orderItems(value *models.Order) []*pb.Item {
+	items := make([]*pb.Item, 0, len(value.OrderList))
+	byID := make(map[uuid.UUID]*pb.Item, len(value.OrderList))
+	for _, item := range value.OrderList {
+		if merged, ok := byID[item.ItemId]; ok {
+			merged.Qty += uint64(item.Qty)
+			continue
+		}
+		byID[item.ItemId] = &pb.Item{Uuid: item.ItemId.String(), Qty: uint64(item.Qty)}
+		items = append(items, byID[item.ItemId])
+	}
+	return items
+}
+
+// Requests inventory refuses as invalid or not satisfiable fail the step, other errors are retried
+func rejectedError(err error) error {
+	switch code := status.Code(err); code {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return &stepFailure{reason: status.Convert(err).Message()}
+	}
+	return err
+}
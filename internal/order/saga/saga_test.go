@@ -0,0 +1,423 @@
+package saga
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/statemachine"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+)
+
+const testToken = 7
+
+func newTestLogger() logger.Logger {
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development: true,
+			Encoding:    "json",
+		},
+	}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	return apiLogger
+}
+
+// Inventory service stand-in keeping stock movements of orders
+type fakeInventory struct {
+	pb.InventoryServiceClient
+	commitErr   error
+	allocate    *pb.AllocateResponse
+	movements   []*pb.Movement
+	reserved    []string
+	extended    []string
+	released    []string
+	returned    []*pb.Item
+	warehouseID string
+}
+
+func (f *fakeInventory) Reserve(ctx context.Context, in *pb.ReserveRequest, opts ...grpc.CallOption) (*pb.ReserveResponse, error) {
+	reservationID := uuid.New().String()
+	f.reserved = append(f.reserved, reservationID)
+	return &pb.ReserveResponse{Status: pb.Status_OK, ReservationId: reservationID}, nil
+}
+
+func (f *fakeInventory) ExtendReservation(ctx context.Context, in *pb.ExtendReservationRequest, opts ...grpc.CallOption) (*pb.ReserveResponse, error) {
+	f.extended = append(f.extended, in.ReservationId)
+	return &pb.ReserveResponse{Status: pb.Status_OK, ReservationId: in.ReservationId}, nil
+}
+
+func (f *fakeInventory) CommitReservation(ctx context.Context, in *pb.ReservationRequest, opts ...grpc.CallOption) (*pb.Response, error) {
+	if f.commitErr != nil {
+		return nil, f.commitErr
+	}
+	f.take(in.OrderId, []*pb.Item{{Uuid: uuid.New().String(), Qty: 1, WarehouseId: f.warehouseID}})
+	return &pb.Response{Status: pb.Status_OK}, nil
+}
+
+func (f *fakeInventory) ReleaseReservation(ctx context.Context, in *pb.ReservationRequest, opts ...grpc.CallOption) (*pb.Response, error) {
+	f.released = append(f.released, in.ReservationId)
+	return &pb.Response{Status: pb.Status_OK}, nil
+}
+
+func (f *fakeInventory) Allocate(ctx context.Context, in *pb.AllocateRequest, opts ...grpc.CallOption) (*pb.AllocateResponse, error) {
+	return f.allocate, nil
+}
+
+func (f *fakeInventory) RemoveItem(ctx context.Context, in *pb.ItemRequest, opts ...grpc.CallOption) (*pb.Response, error) {
+	f.take(in.OrderId, in.Item)
+	return &pb.Response{Status: pb.Status_OK}, nil
+}
+
+func (f *fakeInventory) AddItem(ctx context.Context, in *pb.ItemRequest, opts ...grpc.CallOption) (*pb.Response, error) {
+	for _, item := range in.Item {
+		f.movements = append(f.movements, &pb.Movement{
+			ItemId:      item.Uuid,
+			WarehouseId: item.WarehouseId,
+			Delta:       int64(item.Qty),
+			Reason:      in.Reason,
+			OrderId:     in.OrderId,
+		})
+	}
+	f.returned = append(f.returned, in.Item...)
+	return &pb.Response{Status: pb.Status_OK}, nil
+}
+
+func (f *fakeInventory) ListMovements(ctx context.Context, in *pb.MovementListRequest, opts ...grpc.CallOption) (*pb.MovementListResponse, error) {
+	movements := make([]*pb.Movement, 0)
+	for _, movement := range f.movements {
+		if movement.OrderId == in.OrderId {
+			movements = append(movements, movement)
+		}
+	}
+	return &pb.MovementListResponse{Status: pb.Status_OK, Movements: movements}, nil
+}
+
+func (f *fakeInventory) take(orderID string, items []*pb.Item) {
+	for _, item := range items {
+		f.movements = append(f.movements, &pb.Movement{
+			ItemId:      item.Uuid,
+			WarehouseId: item.WarehouseId,
+			Delta:       -int64(item.Qty),
+			Reason:      pb.MovementReason_ReasonOrder,
+			OrderId:     orderID,
+		})
+	}
+}
+
+type testSaga struct {
+	saga      order.Saga
+	orderRepo *mock.MockRepository
+	payment   *mock.MockPaymentGateway
+	shipping  *mock.MockShippingGateway
+	inventory *fakeInventory
+}
+
+func newTestSaga(ctrl *gomock.Controller) *testSaga {
+	ts := &testSaga{
+		orderRepo: mock.NewMockRepository(ctrl),
+		payment:   mock.NewMockPaymentGateway(ctrl),
+		shipping:  mock.NewMockShippingGateway(ctrl),
+		inventory: &fakeInventory{warehouseID: uuid.New().String()},
+	}
+	ts.saga = NewOrderSaga(&config.Config{}, ts.orderRepo, statemachine.NewOrderStateMachine(), ts.inventory, ts.payment, ts.shipping, newTestLogger())
+	return ts
+}
+
+func newTestOrder(status models.OrderStatus) *models.Order {
+	return &models.Order{
+		OrderId:   uuid.New(),
+		Status:    status,
+		Sum:       20,
+		OrderList: []*models.OrderItem{{ItemId: uuid.New(), Cost: 10, Qty: 2, Sum: 20}},
+	}
+}
+
+func TestOrderSaga_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := newTestSaga(ctrl)
+	ctx := context.Background()
+	reservationID := uuid.New()
+	value := newTestOrder(models.OrderStatusConfirmed)
+	value.ReservationID = &reservationID
+
+	// Confirmed stage reserves, charges and packages the order
+	ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(nil, nil)
+	ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).Times(3)
+	ts.payment.EXPECT().Charge(gomock.Any(), value).Return("payment-1", nil)
+
+	saga, err := ts.saga.Run(ctx, value, testToken)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPackaged, value.Status)
+	require.Equal(t, models.OrderSagaStepShip, saga.Step)
+	require.Equal(t, models.OrderSagaStateRunning, saga.State)
+	require.Equal(t, &reservationID, saga.ReservationID)
+	require.Equal(t, "payment-1", *saga.PaymentID)
+	require.Empty(t, ts.inventory.reserved)
+	require.Equal(t, []string{reservationID.String()}, ts.inventory.extended)
+
+	// Packaged stage ships the order and completes saga
+	ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(saga, nil)
+	ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil)
+	ts.shipping.EXPECT().Ship(gomock.Any(), value).Return("shipment-1", nil)
+
+	saga, err = ts.saga.Run(ctx, value, testToken)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusInDelivery, value.Status)
+	require.Equal(t, models.OrderSagaStateCompleted, saga.State)
+	require.Equal(t, "shipment-1", *saga.ShipmentID)
+}
+
+func TestOrderSaga_Compensate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotEnoughStock", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		ts.inventory.commitErr = status.Error(codes.FailedPrecondition, "not enough stock")
+		ts.inventory.allocate = &pb.AllocateResponse{Status: pb.Status_NotEnoughAvailable}
+		value := newTestOrder(models.OrderStatusConfirmed)
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(nil, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).AnyTimes()
+		ts.payment.EXPECT().Charge(gomock.Any(), value).Return("payment-1", nil)
+		ts.payment.EXPECT().Refund(gomock.Any(), value, "payment-1").Return(nil)
+
+		saga, err := ts.saga.Run(context.Background(), value, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, value.Status)
+		require.Equal(t, models.OrderSagaStateCompensated, saga.State)
+		require.Equal(t, models.OrderSagaStepReserve, saga.Step)
+		require.Equal(t, "Inventory not have items for order", saga.Failure)
+		require.Len(t, ts.inventory.reserved, 1)
+		require.Equal(t, ts.inventory.reserved, ts.inventory.released)
+	})
+
+	t.Run("ShipmentDeclined", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		value := newTestOrder(models.OrderStatusPackaged)
+		reservationID := uuid.New()
+		paymentID := "payment-1"
+		itemID := uuid.New().String()
+		ts.inventory.take(value.OrderId.String(), []*pb.Item{{Uuid: itemID, Qty: 2, WarehouseId: ts.inventory.warehouseID}})
+		persisted := &models.OrderSaga{
+			OrderId:       value.OrderId,
+			Step:          models.OrderSagaStepShip,
+			State:         models.OrderSagaStateRunning,
+			ReservationID: &reservationID,
+			PaymentID:     &paymentID,
+		}
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(persisted, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).AnyTimes()
+		ts.shipping.EXPECT().Ship(gomock.Any(), value).Return("", errors.Wrap(order.ErrDeclined, "courier"))
+		ts.payment.EXPECT().Refund(gomock.Any(), value, paymentID).Return(nil)
+
+		saga, err := ts.saga.Run(context.Background(), value, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, value.Status)
+		require.Equal(t, models.OrderSagaStateCompensated, saga.State)
+		require.Equal(t, "Shipment declined", saga.Failure)
+		require.Equal(t, []*pb.Item{{Uuid: itemID, Qty: 2, WarehouseId: ts.inventory.warehouseID}}, ts.inventory.returned)
+		require.Equal(t, []string{reservationID.String()}, ts.inventory.released)
+	})
+
+	t.Run("ResumeAfterFailure", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		value := newTestOrder(models.OrderStatusPackaged)
+		paymentID := "payment-1"
+		persisted := &models.OrderSaga{
+			OrderId:   value.OrderId,
+			Step:      models.OrderSagaStepPackage,
+			State:     models.OrderSagaStateCompensating,
+			PaymentID: &paymentID,
+			Failure:   "Shipment declined",
+		}
+
+		// Refund fails, compensated steps stay done and saga resumes from refund
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(persisted, nil)
+		ts.payment.EXPECT().Refund(gomock.Any(), value, paymentID).Return(errors.New("connection refused"))
+
+		saga, err := ts.saga.Run(context.Background(), value, testToken)
+		require.Error(t, err)
+		require.Equal(t, models.OrderStatusPackaged, value.Status)
+		require.Equal(t, models.OrderSagaStepPackage, saga.Step)
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(saga, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).Times(3)
+		ts.payment.EXPECT().Refund(gomock.Any(), value, paymentID).Return(nil)
+
+		saga, err = ts.saga.Run(context.Background(), value, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, value.Status)
+		require.Equal(t, models.OrderSagaStateCompensated, saga.State)
+	})
+}
+
+func TestOrderSaga_Cancel(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	cancellation := &models.OrderStatusHistory{Actor: models.OrderStatusActorUser, ActorID: &userID, Reason: "changed my mind"}
+
+	t.Run("Packaged", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		value := newTestOrder(models.OrderStatusPackaged)
+		reservationID := uuid.New()
+		paymentID := "payment-1"
+		itemID := uuid.New().String()
+		ts.inventory.take(value.OrderId.String(), []*pb.Item{{Uuid: itemID, Qty: 2, WarehouseId: ts.inventory.warehouseID}})
+		persisted := &models.OrderSaga{
+			OrderId:       value.OrderId,
+			Step:          models.OrderSagaStepShip,
+			State:         models.OrderSagaStateRunning,
+			ReservationID: &reservationID,
+			PaymentID:     &paymentID,
+		}
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(persisted, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), persisted, int64(testToken)).Return(nil)
+
+		saga, err := ts.saga.Cancel(context.Background(), value, cancellation, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderSagaStateCompensating, saga.State)
+		require.Equal(t, "Cancelled by user", saga.Failure)
+		require.Equal(t, models.OrderStatusActorUser, saga.CancelActor)
+		require.Equal(t, &userID, saga.CancelActorID)
+		require.Equal(t, "changed my mind", saga.CancelReason)
+		require.Equal(t, models.OrderStatusPackaged, value.Status)
+
+		// Next run of the stage rolls back packaging, charge and reservation
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(saga, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).AnyTimes()
+		ts.payment.EXPECT().Refund(gomock.Any(), value, paymentID).Return(nil)
+
+		saga, err = ts.saga.Run(context.Background(), value, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, value.Status)
+		require.Equal(t, models.OrderSagaStateCompensated, saga.State)
+		require.Equal(t, []*pb.Item{{Uuid: itemID, Qty: 2, WarehouseId: ts.inventory.warehouseID}}, ts.inventory.returned)
+		require.Equal(t, []string{reservationID.String()}, ts.inventory.released)
+	})
+
+	t.Run("NotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		value := newTestOrder(models.OrderStatusConfirmed)
+		reservationID := uuid.New()
+		value.ReservationID = &reservationID
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(nil, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil)
+
+		saga, err := ts.saga.Cancel(context.Background(), value, cancellation, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderSagaStateCompensating, saga.State)
+		require.Equal(t, &reservationID, saga.ReservationID)
+
+		// Reservation made when order was created is released
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(saga, nil)
+		ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).AnyTimes()
+
+		saga, err = ts.saga.Run(context.Background(), value, testToken)
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, value.Status)
+		require.Equal(t, models.OrderSagaStateCompensated, saga.State)
+		require.Equal(t, []string{reservationID.String()}, ts.inventory.released)
+	})
+
+	t.Run("AlreadyCompensating", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ts := newTestSaga(ctrl)
+		value := newTestOrder(models.OrderStatusPackaged)
+		persisted := &models.OrderSaga{
+			OrderId: value.OrderId,
+			Step:    models.OrderSagaStepShip,
+			State:   models.OrderSagaStateCompensating,
+			Failure: "Shipment declined",
+		}
+
+		ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(persisted, nil)
+
+		saga, err := ts.saga.Cancel(context.Background(), value, cancellation, testToken)
+		require.NoError(t, err)
+		require.Equal(t, "Shipment declined", saga.Failure)
+	})
+}
+
+func TestOrderSaga_Retry(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := newTestSaga(ctrl)
+	value := newTestOrder(models.OrderStatusConfirmed)
+
+	// Transient failure keeps saga running from the failed step
+	ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(nil, nil)
+	ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil)
+	ts.payment.EXPECT().Charge(gomock.Any(), value).Return("", errors.New("timeout"))
+
+	saga, err := ts.saga.Run(context.Background(), value, testToken)
+	require.Error(t, err)
+	require.Equal(t, models.OrderStatusConfirmed, value.Status)
+	require.Equal(t, models.OrderSagaStateRunning, saga.State)
+	require.Equal(t, models.OrderSagaStepCharge, saga.Step)
+	require.Empty(t, ts.inventory.released)
+
+	// Package step of restarted saga does not take stock out twice
+	ts.inventory.take(value.OrderId.String(), []*pb.Item{{Uuid: uuid.New().String(), Qty: 2, WarehouseId: ts.inventory.warehouseID}})
+	ts.inventory.commitErr = errors.New("commit is not expected")
+	ts.orderRepo.EXPECT().GetSaga(gomock.Any(), value.OrderId).Return(saga, nil)
+	ts.orderRepo.EXPECT().SaveSaga(gomock.Any(), gomock.Any(), int64(testToken)).Return(nil).Times(2)
+	ts.payment.EXPECT().Charge(gomock.Any(), value).Return("payment-1", nil)
+
+	saga, err = ts.saga.Run(context.Background(), value, testToken)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPackaged, value.Status)
+	require.Equal(t, models.OrderSagaStepShip, saga.Step)
+}
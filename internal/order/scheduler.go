@@ -1,7 +1,11 @@
 package order
 
-import "github.com/go-co-op/gocron"
+import (
+	"context"
+	"github.com/go-co-op/gocron"
+)
 
 type Scheduler interface {
 	MapCron(*gocron.Scheduler)
+	Start(ctx context.Context) error
 }
@@ -2,173 +2,304 @@ package scheduler
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"github.com/engineerXIII/maiSystemBackend/config"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
 	"github.com/engineerXIII/maiSystemBackend/internal/order"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
-	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
 	"github.com/go-co-op/gocron"
-	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	amqp "github.com/rabbitmq/amqp091-go"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"math/rand"
 	"os"
 	"time"
 )
 
+// Redis variables
+const (
+	basePrefix = "api-orders:"
+	taskPrefix = "api-orders-task:"
+	// Queued mark outlives retries of a stage, so active orders sweep does not queue it twice
+	taskDuration = 24 * 3600
+)
+
+const (
+	processTimeout = 30 * time.Second
+	defaultWorkers = 1
+	// Order cache index is walked in batches of this size when pruned
+	cacheIndexBatch = 100
+)
+
+// Order stayed in the same stage, e.g. was reduced to available items, and the stage runs again
+var errStageRepeated = errors.New("stage has to be repeated")
+
 type orderScheduler struct {
-	cfg         *config.Config
-	orderRepo   *order.RedisRepository
-	grpcClient  pb.InventoryServiceClient
-	amqqChannel *amqp.Channel
-	amqpQueue   *amqp.Queue
-	logger      logger.Logger
+	cfg          *config.Config
+	orderRepo    order.Repository
+	redisRepo    order.RedisRepository
+	stateMachine order.StateMachine
+	queue        order.Queue
+	locker       order.Locker
+	saga         order.Saga
+	amqqChannel  *amqp.Channel
+	amqpQueue    *amqp.Queue
+	logger       logger.Logger
+}
+
+func NewOrderScheduler(cfg *config.Config, amqqChannel *amqp.Channel, amqpQueue *amqp.Queue, orderRepo order.Repository, redisRepo order.RedisRepository, stateMachine order.StateMachine, queue order.Queue, locker order.Locker, saga order.Saga, logger logger.Logger) order.Scheduler {
+	return &orderScheduler{cfg: cfg, saga: saga, amqqChannel: amqqChannel, amqpQueue: amqpQueue, orderRepo: orderRepo, redisRepo: redisRepo, stateMachine: stateMachine, queue: queue, locker: locker, logger: logger}
+}
+
+// Queue active orders which are not queued yet, picks up new orders and stages lost by a crash
+func (o *orderScheduler) MapCron(cron *gocron.Scheduler) {
+	cron.Every(5).Second().Do(func() {
+		ctx, shutdown := context.WithTimeout(context.Background(), 30*time.Second)
+		defer shutdown()
+
+		o.sweep(ctx)
+	})
+
+	cron.Every(10).Minutes().Do(func() {
+		ctx, shutdown := context.WithTimeout(context.Background(), 30*time.Second)
+		defer shutdown()
+
+		o.pruneCacheIndex(ctx)
+	})
+}
+
+// Walk order cache index, so entries of expired cached orders are dropped and index does not grow forever
+func (o *orderScheduler) pruneCacheIndex(ctx context.Context) {
+	it := o.redisRepo.OrderKeys(cacheIndexBatch)
+	cached := 0
+	for it.Next(ctx) {
+		cached++
+	}
+	if err := it.Err(); err != nil {
+		o.logger.Errorf("[CRON][CACHE]: Order cache index prune failed: %s", err)
+		return
+	}
+	o.logger.Debugf("[CRON][CACHE]: Order cache index pruned, %d orders cached", cached)
 }
 
-func NewOrderScheduler(cfg *config.Config, amqqChannel *amqp.Channel, amqpQueue *amqp.Queue, orderRepo *order.RedisRepository, logger logger.Logger) order.Scheduler {
-	pemServerCA, err := os.ReadFile("ssl/root.pem")
+// Queue current stage of every active order, stages queued before are skipped by enqueue
+func (o *orderScheduler) sweep(ctx context.Context) {
+	tasks, err := o.orderRepo.GetActiveOrderTasks(ctx)
 	if err != nil {
-		logger.Error(err)
-		panic(err)
+		o.logger.Errorf("[CRON][AUTOSTATUS]: Active orders select failed: %s", err)
+		return
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(pemServerCA) {
-		logger.Errorf("failed to add server CA's certificate")
+	if len(tasks) == 0 {
+		o.logger.Debug("[CRON][AUTOSTATUS]: Nothing to update in orders")
+		return
 	}
-	// Create the credentials and return it
-	tlsConfig := &tls.Config{
-		RootCAs: certPool,
+
+	for _, task := range tasks {
+		if err = o.enqueue(ctx, task); err != nil {
+			o.logger.Errorf("[CRON][AUTOSTATUS]: Order %s enqueue failed: %s", task.OrderId, err)
+		}
 	}
-	connCred := credentials.NewTLS(tlsConfig)
-	conn, err := grpc.Dial(cfg.Service.Inventory, grpc.WithTransportCredentials(connCred))
+}
+
+// Start workers consuming processing queue, they stop when context is done
+func (o *orderScheduler) Start(ctx context.Context) error {
+	if err := o.queue.Declare(); err != nil {
+		return err
+	}
+
+	workers := o.cfg.Processing.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	hostname, _ := os.Hostname()
+	deliveries, err := o.queue.Consume(ctx, fmt.Sprintf("order-processor-%s-%d", hostname, os.Getpid()), workers)
 	if err != nil {
-		logger.Fatalf("GRPC not connect: %v", err)
+		return err
 	}
-	client := pb.NewInventoryServiceClient(conn)
-	return &orderScheduler{cfg: cfg, grpcClient: client, amqqChannel: amqqChannel, amqpQueue: amqpQueue, orderRepo: orderRepo, logger: logger}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for delivery := range deliveries {
+				o.handleDelivery(ctx, delivery)
+			}
+		}()
+	}
+	o.logger.Infof("Order processing started with %d workers", workers)
+	return nil
 }
 
-func (o *orderScheduler) MapCron(cron *gocron.Scheduler) {
-	// Auto status change
-	cron.Every(5).Second().Do(func() {
-		repo := *o.orderRepo
-		ctx, shutdown := context.WithTimeout(context.Background(), 30*time.Second)
-		defer shutdown()
+// Process delivered task, failed stage is moved to retry queue
+func (o *orderScheduler) handleDelivery(ctx context.Context, delivery amqp.Delivery) {
+	task := &models.OrderTask{}
+	if err := json.Unmarshal(delivery.Body, task); err != nil {
+		o.logger.Errorf("[PROCESSING]: Malformed task dropped: %s", err)
+		_ = delivery.Ack(false)
+		return
+	}
+
+	c, cancel := context.WithTimeout(ctx, processTimeout)
+	err := o.processTask(c, task)
+	cancel()
+	if err == nil {
+		_ = delivery.Ack(false)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errStageRepeated):
+		o.logger.Infof("[PROCESSING]: Order %s %s stage is repeated later", task.OrderId, task.Status.ToString())
+	case errors.Is(err, order.ErrLocked):
+		o.logger.Infof("[PROCESSING]: Order %s is processed by another replica, %s stage is checked later", task.OrderId, task.Status.ToString())
+	default:
+		o.logger.Errorf("[PROCESSING]: Order %s %s stage failed, retrying later: %s", task.OrderId, task.Status.ToString(), err)
+	}
+	if err = o.queue.PublishRetry(ctx, task); err != nil {
+		o.logger.Errorf("[PROCESSING]: Order %s retry publish failed: %s", task.OrderId, err)
+		_ = delivery.Nack(false, true)
+		return
+	}
+	_ = delivery.Ack(false)
+}
 
-		keys, err := repo.GetOrderKeysCtx(context.Background())
-		if err != nil {
-			o.logger.Errorf("[CRON][AUTOSTATUS]: Order keys scan redis failed: %s", err)
+// Queue order stage once, repeated calls for the same stage are ignored.
+// Mark of the stage is dropped when publish fails, so active orders sweep queues it again
+func (o *orderScheduler) enqueue(ctx context.Context, task *models.OrderTask) error {
+	if !isActive(task.Status) {
+		return nil
+	}
+	key := fmt.Sprintf("%s%s:%d", taskPrefix, task.OrderId, task.Status)
+	ok, err := o.redisRepo.SetOrderTaskCtx(ctx, key, taskDuration)
+	if err != nil || !ok {
+		return err
+	}
+	if err = o.queue.Publish(ctx, task); err != nil {
+		if delErr := o.redisRepo.DeleteOrderTaskCtx(ctx, key); delErr != nil {
+			o.logger.Errorf("[PROCESSING]: Order %s queued mark delete failed: %s", task.OrderId, delErr)
 		}
+		return err
+	}
+	return nil
+}
 
-		var keyLen int = len(keys)
-		if keyLen == 0 {
-			o.logger.Debug("[CRON][AUTOSTATUS]: Nothing to update in orders")
-			return
+// Move order through its current stage holding order lock, so one replica performs the transition.
+// Tasks of stages order already left are skipped, returned error means the stage has to be retried
+func (o *orderScheduler) processTask(ctx context.Context, task *models.OrderTask) error {
+	lock, ctx, err := o.locker.Lock(ctx, task.OrderId)
+	if errors.Is(err, sql.ErrNoRows) {
+		o.logger.Debugf("[PROCESSING]: Order %s deleted", task.OrderId)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(context.Background()); err != nil {
+			o.logger.Errorf("[PROCESSING]: Order %s unlock failed: %s", task.OrderId, err)
 		}
-		index_key := rand.Intn(keyLen)
-		value, err := repo.GetOrderByIDCtx(ctx, keys[index_key])
-
-		switch value.Status {
-		default:
-			return
-		case models.OrderStatusCreated:
-			value.Status = value.Status + 1
-			value.StatusMessage = value.Status.ToString()
-			break
-		case models.OrderStatusConfirmed:
-			c, cancel := context.WithTimeout(ctx, time.Second*5)
-			defer cancel()
-			grpcPaylod := &pb.ItemRequest{Item: []*pb.Item{}}
-			for _, item := range value.OrderList {
-				grpcPaylod.Item = append(grpcPaylod.Item, &pb.Item{
-					Uuid: item.ItemId.String(),
-					Qty:  uint64(item.Qty),
-				})
-			}
-			resp, err := o.grpcClient.CheckItem(c, grpcPaylod)
-			if err != nil {
-				o.logger.Error(err)
-				panic(err)
-			}
-			o.logger.Debugf("Order %v, %v", value.OrderId, resp.Status)
-			if resp.Status == pb.Status_OK {
-				o.logger.Infof("Order %v fully packaged", value.OrderId)
-				value.Status = value.Status + 1
-				value.StatusMessage = value.Status.ToString()
-			} else {
-				for i, item := range value.OrderList {
-					uid, _ := uuid.Parse(resp.Items[i].Item.Uuid)
-					if item.ItemId == uid {
-						if resp.Items[i].Item.Qty == 0 {
-							o.logger.Infof("Inventory not have items for order %v. Cancelling...", value.OrderId)
-							value.Status = models.OrderStatusCancelled
-							break
-						}
-						value.OrderList[i].Qty = int(resp.Items[i].Item.Qty)
-					}
-				}
-				if value.Status == models.OrderStatusCancelled {
-					break
-				}
-			}
+	}()
 
-			o.logger.Infof("Inventory give items for order %v", value.OrderId)
-			req := &pb.ItemRequest{Item: []*pb.Item{}}
-			for _, item := range resp.Items {
-				req.Item = append(req.Item, item.Item)
-			}
-			_, err = o.grpcClient.RemoveItem(ctx, req)
-			if err != nil {
-				o.logger.Error(err)
-			}
-			o.logger.Debugf("Order %s, inventory %s", value.OrderId, resp.Status)
-			break
-		case models.OrderStatusPackaged:
-			value.Status = value.Status + 1
-			value.StatusMessage = value.Status.ToString()
-			break
-		case models.OrderStatusInDelivery:
-			value.Status = value.Status + 1
-			value.StatusMessage = value.Status.ToString()
-			break
-		case models.OrderStatusCompleted:
-			o.logger.Debugf("Order %s removed from processing as completed", value.OrderId)
-			repo.DeleteOrderCtx(ctx, keys[index_key])
-			return
-			//case models.OrderStatusCancelled:
-			//	o.logger.Debugf("Order %s removed from processing as cancelled", value.OrderId)
-			//	repo.DeleteOrderCtx(ctx, keys[index_key])
-			//	return
+	value, err := o.orderRepo.GetOrderByID(ctx, task.OrderId)
+	if err != nil {
+		return errors.Wrap(err, "orderRepo.GetOrderByID")
+	}
+	if value.Status != task.Status {
+		o.logger.Debugf("[PROCESSING]: Order %s already left %s stage", task.OrderId, task.Status.ToString())
+		return nil
+	}
+
+	fromStatus := value.Status
+	var saga *models.OrderSaga
+	switch value.Status {
+	default:
+		return nil
+	case models.OrderStatusCreated:
+		if err = o.stateMachine.Next(value); err != nil {
+			o.logger.Errorf("[PROCESSING]: Order %s status change failed: %s", value.OrderId, err)
+			return nil
 		}
-		err = repo.SetOrderCtx(ctx, keys[index_key], 3600, value)
-		if err != nil {
-			o.logger.Errorf("[CRON][AUTOSTATUS]: Order update fail: %s", err)
+	case models.OrderStatusConfirmed, models.OrderStatusPackaged:
+		if saga, err = o.saga.Run(ctx, value, lock.Token()); err != nil {
+			return errors.Wrap(err, "saga.Run")
 		}
+	case models.OrderStatusInDelivery:
+		if err = o.stateMachine.Next(value); err != nil {
+			o.logger.Errorf("[PROCESSING]: Order %s status change failed: %s", value.OrderId, err)
+			return nil
+		}
+	}
 
-		jsonStr, _ := json.Marshal(models.OrderStatusNotify{
-			OrderId:       value.OrderId,
-			Status:        value.Status,
-			StatusMessage: value.StatusMessage,
-		})
+	var history *models.OrderStatusHistory
+	if value.Status != fromStatus {
+		history = stageHistory(value, fromStatus, saga)
+	}
+	// Write of replica which lost the lock meanwhile is rejected by newer fencing token
+	if _, err = o.orderRepo.UpdateFenced(ctx, value, history, lock.Token()); err != nil {
+		return errors.Wrap(err, "orderRepo.UpdateFenced")
+	}
+	if err = o.redisRepo.DeleteOrderCtx(ctx, basePrefix+value.OrderId.String()); err != nil {
+		o.logger.Errorf("[PROCESSING]: Order cache invalidate fail: %s", err)
+	}
 
-		o.logger.Debugf("[CRON][AUTOSTATUS]: Order notify JSON: %s", string(jsonStr))
-
-		err = o.amqqChannel.PublishWithContext(ctx,
-			"",
-			o.amqpQueue.Name,
-			false,
-			false,
-			amqp.Publishing{
-				ContentType: "application/json",
-				Body:        jsonStr,
-			})
-		if err != nil {
-			o.logger.Errorf("[CRON][AUTOSTATUS]: Order notify publish error: %s", err)
-		}
+	o.notify(ctx, value)
 
+	if value.Status == fromStatus {
+		return errStageRepeated
+	}
+	if err = o.enqueue(ctx, &models.OrderTask{OrderId: value.OrderId, Status: value.Status}); err != nil {
+		// Stage is not marked as queued after failed publish, active orders sweep queues it later
+		o.logger.Errorf("[PROCESSING]: Order %s enqueue failed: %s", value.OrderId, err)
+	}
+	return nil
+}
+
+// Status transition made by order processing. Order cancelled by saga compensation after
+// cancellation request is recorded on behalf of user or admin who requested it
+func stageHistory(value *models.Order, from models.OrderStatus, saga *models.OrderSaga) *models.OrderStatusHistory {
+	history := &models.OrderStatusHistory{
+		OrderId:    value.OrderId,
+		FromStatus: from,
+		ToStatus:   value.Status,
+		Actor:      models.OrderStatusActorScheduler,
+		Reason:     "Automatic order processing",
+	}
+	if saga == nil || value.Status != models.OrderStatusCancelled {
+		return history
+	}
+	if saga.CancelActor == "" {
+		history.Reason = saga.Failure
+		return history
+	}
+	history.Actor = saga.CancelActor
+	history.ActorID = saga.CancelActorID
+	history.Reason = saga.CancelReason
+	return history
+}
+
+// Publish order status to notification queue
+func (o *orderScheduler) notify(ctx context.Context, value *models.Order) {
+	jsonStr, _ := json.Marshal(models.OrderStatusNotify{
+		OrderId:       value.OrderId,
+		Status:        value.Status,
+		StatusMessage: value.StatusMessage,
 	})
+
+	o.logger.Debugf("[PROCESSING]: Order notify JSON: %s", string(jsonStr))
+
+	err := o.amqqChannel.PublishWithContext(ctx,
+		"",
+		o.amqpQueue.Name,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        jsonStr,
+		})
+	if err != nil {
+		o.logger.Errorf("[PROCESSING]: Order notify publish error: %s", err)
+	}
+}
+
+// Order statuses processed by scheduler
+func isActive(status models.OrderStatus) bool {
+	return status >= models.OrderStatusCreated && status < models.OrderStatusCompleted
 }
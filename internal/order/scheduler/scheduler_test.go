@@ -0,0 +1,356 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/repository"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/statemachine"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+)
+
+func newTestLogger() logger.Logger {
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development: true,
+			Encoding:    "json",
+		},
+	}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	return apiLogger
+}
+
+// Records acknowledgement of delivery
+type testAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *testAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *testAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *testAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// Locker granting order lock once, lock has to be released
+func expectLock(ctrl *gomock.Controller) *mock.MockLocker {
+	mockLocker := mock.NewMockLocker(ctrl)
+	mockLock := mock.NewMockLock(ctrl)
+	mockLocker.EXPECT().Lock(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, orderID uuid.UUID) (order.Lock, context.Context, error) {
+		return mockLock, ctx, nil
+	})
+	mockLock.EXPECT().Unlock(gomock.Any()).Return(nil)
+	return mockLocker
+}
+
+func TestOrderScheduler_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockQueue := mock.NewMockQueue(ctrl)
+	scheduler := &orderScheduler{
+		cfg:          &config.Config{},
+		orderRepo:    mockOrderRepo,
+		redisRepo:    mockRedisRepo,
+		stateMachine: statemachine.NewOrderStateMachine(),
+		queue:        mockQueue,
+		logger:       newTestLogger(),
+	}
+
+	ctx := context.Background()
+	task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusCreated}
+	key := fmt.Sprintf("%s%s:%d", taskPrefix, task.OrderId, task.Status)
+
+	mockRedisRepo.EXPECT().SetOrderTaskCtx(gomock.Any(), key, taskDuration).Return(true, nil)
+	mockQueue.EXPECT().Publish(gomock.Any(), gomock.Eq(task)).Return(nil)
+	require.NoError(t, scheduler.enqueue(ctx, task))
+
+	// Stage queued before is not published again
+	mockRedisRepo.EXPECT().SetOrderTaskCtx(gomock.Any(), key, taskDuration).Return(false, nil)
+	require.NoError(t, scheduler.enqueue(ctx, task))
+
+	// Final statuses are never queued
+	require.NoError(t, scheduler.enqueue(ctx, &models.OrderTask{OrderId: task.OrderId, Status: models.OrderStatusCompleted}))
+}
+
+func TestOrderScheduler_EnqueuePublishFailed(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer redisClient.Close()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockQueue := mock.NewMockQueue(ctrl)
+	scheduler := &orderScheduler{
+		cfg:          &config.Config{},
+		orderRepo:    mockOrderRepo,
+		redisRepo:    repository.NewOrderRedisRepo(redisClient),
+		stateMachine: statemachine.NewOrderStateMachine(),
+		queue:        mockQueue,
+		logger:       newTestLogger(),
+	}
+
+	ctx := context.Background()
+	task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusConfirmed}
+	key := fmt.Sprintf("%s%s:%d", taskPrefix, task.OrderId, task.Status)
+
+	mockQueue.EXPECT().Publish(gomock.Any(), gomock.Eq(task)).Return(errors.New("channel closed"))
+	require.Error(t, scheduler.enqueue(ctx, task))
+	require.False(t, mr.Exists(key))
+
+	// Next sweep queues the stage which was not published
+	mockOrderRepo.EXPECT().GetActiveOrderTasks(gomock.Any()).Return([]*models.OrderTask{task}, nil)
+	mockQueue.EXPECT().Publish(gomock.Any(), gomock.Eq(task)).Return(nil)
+	scheduler.sweep(ctx)
+	require.True(t, mr.Exists(key))
+
+	// Published stage is not queued by later sweeps
+	mockOrderRepo.EXPECT().GetActiveOrderTasks(gomock.Any()).Return([]*models.OrderTask{task}, nil)
+	scheduler.sweep(ctx)
+}
+
+func TestOrderScheduler_PruneCacheIndex(t *testing.T) {
+	t.Parallel()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer redisClient.Close()
+
+	redisRepo := repository.NewOrderRedisRepo(redisClient)
+	scheduler := &orderScheduler{cfg: &config.Config{}, redisRepo: redisRepo, logger: newTestLogger()}
+
+	ctx := context.Background()
+	expired, cached := uuid.New(), uuid.New()
+	require.NoError(t, redisRepo.SetOrderCtx(ctx, basePrefix+expired.String(), 10, &models.Order{OrderId: expired}))
+	require.NoError(t, redisRepo.SetOrderCtx(ctx, basePrefix+cached.String(), 3600, &models.Order{OrderId: cached}))
+	mr.FastForward(time.Minute)
+
+	scheduler.pruneCacheIndex(ctx)
+
+	members, err := mr.ZMembers("api-orders-index")
+	require.NoError(t, err)
+	require.Equal(t, []string{basePrefix + cached.String()}, members)
+}
+
+func TestOrderScheduler_HandleDelivery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StaleStage", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockOrderRepo := mock.NewMockRepository(ctrl)
+		mockQueue := mock.NewMockQueue(ctrl)
+		mockLocker := expectLock(ctrl)
+		scheduler := &orderScheduler{
+			cfg:          &config.Config{},
+			orderRepo:    mockOrderRepo,
+			stateMachine: statemachine.NewOrderStateMachine(),
+			queue:        mockQueue,
+			locker:       mockLocker,
+			logger:       newTestLogger(),
+		}
+
+		orderID := uuid.New()
+		body, err := json.Marshal(&models.OrderTask{OrderId: orderID, Status: models.OrderStatusCreated})
+		require.NoError(t, err)
+		acknowledger := &testAcknowledger{}
+
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), orderID).
+			Return(&models.Order{OrderId: orderID, Status: models.OrderStatusConfirmed}, nil)
+
+		scheduler.handleDelivery(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Body: body})
+		require.True(t, acknowledger.acked)
+		require.False(t, acknowledger.nacked)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockOrderRepo := mock.NewMockRepository(ctrl)
+		mockQueue := mock.NewMockQueue(ctrl)
+		mockLocker := expectLock(ctrl)
+		scheduler := &orderScheduler{
+			cfg:          &config.Config{},
+			orderRepo:    mockOrderRepo,
+			stateMachine: statemachine.NewOrderStateMachine(),
+			queue:        mockQueue,
+			locker:       mockLocker,
+			logger:       newTestLogger(),
+		}
+
+		task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusCreated}
+		body, err := json.Marshal(task)
+		require.NoError(t, err)
+		acknowledger := &testAcknowledger{}
+
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), task.OrderId).Return(nil, errors.New("connection refused"))
+		mockQueue.EXPECT().PublishRetry(gomock.Any(), gomock.Eq(task)).Return(nil)
+
+		scheduler.handleDelivery(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Body: body})
+		require.True(t, acknowledger.acked)
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockQueue := mock.NewMockQueue(ctrl)
+		mockLocker := mock.NewMockLocker(ctrl)
+		scheduler := &orderScheduler{
+			cfg:          &config.Config{},
+			stateMachine: statemachine.NewOrderStateMachine(),
+			queue:        mockQueue,
+			locker:       mockLocker,
+			logger:       newTestLogger(),
+		}
+
+		task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusCreated}
+		body, err := json.Marshal(task)
+		require.NoError(t, err)
+		acknowledger := &testAcknowledger{}
+
+		// Other replica holds the order, stage is checked again after retry delay
+		mockLocker.EXPECT().Lock(gomock.Any(), task.OrderId).Return(nil, nil, errors.Wrap(order.ErrLocked, "orderLocker.Lock"))
+		mockQueue.EXPECT().PublishRetry(gomock.Any(), gomock.Eq(task)).Return(nil)
+
+		scheduler.handleDelivery(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Body: body})
+		require.True(t, acknowledger.acked)
+		require.False(t, acknowledger.nacked)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockQueue := mock.NewMockQueue(ctrl)
+		mockLocker := mock.NewMockLocker(ctrl)
+		scheduler := &orderScheduler{
+			cfg:          &config.Config{},
+			stateMachine: statemachine.NewOrderStateMachine(),
+			queue:        mockQueue,
+			locker:       mockLocker,
+			logger:       newTestLogger(),
+		}
+
+		task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusCreated}
+		body, err := json.Marshal(task)
+		require.NoError(t, err)
+		acknowledger := &testAcknowledger{}
+
+		// No fencing token is issued for deleted order, its task is dropped without retry
+		mockLocker.EXPECT().Lock(gomock.Any(), task.OrderId).Return(nil, nil, errors.Wrap(sql.ErrNoRows, "orderLocker.Lock.IssueFencingToken"))
+
+		scheduler.handleDelivery(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Body: body})
+		require.True(t, acknowledger.acked)
+		require.False(t, acknowledger.nacked)
+	})
+
+	t.Run("RetryPublishFailed", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockOrderRepo := mock.NewMockRepository(ctrl)
+		mockQueue := mock.NewMockQueue(ctrl)
+		mockLocker := expectLock(ctrl)
+		scheduler := &orderScheduler{
+			cfg:          &config.Config{},
+			orderRepo:    mockOrderRepo,
+			stateMachine: statemachine.NewOrderStateMachine(),
+			queue:        mockQueue,
+			locker:       mockLocker,
+			logger:       newTestLogger(),
+		}
+
+		task := &models.OrderTask{OrderId: uuid.New(), Status: models.OrderStatusCreated}
+		body, err := json.Marshal(task)
+		require.NoError(t, err)
+		acknowledger := &testAcknowledger{}
+
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), task.OrderId).Return(nil, errors.New("connection refused"))
+		mockQueue.EXPECT().PublishRetry(gomock.Any(), gomock.Eq(task)).Return(errors.New("channel closed"))
+
+		scheduler.handleDelivery(context.Background(), amqp.Delivery{Acknowledger: acknowledger, Body: body})
+		require.False(t, acknowledger.acked)
+		require.True(t, acknowledger.nacked)
+		require.True(t, acknowledger.requeue)
+	})
+}
+
+func TestStageHistory(t *testing.T) {
+	t.Parallel()
+
+	value := &models.Order{OrderId: uuid.New(), Status: models.OrderStatusPackaged}
+	history := stageHistory(value, models.OrderStatusConfirmed, &models.OrderSaga{State: models.OrderSagaStateRunning})
+	require.Equal(t, models.OrderStatusActorScheduler, history.Actor)
+	require.Equal(t, models.OrderStatusPackaged, history.ToStatus)
+
+	value.Status = models.OrderStatusCancelled
+	history = stageHistory(value, models.OrderStatusPackaged, &models.OrderSaga{Failure: "Shipment declined"})
+	require.Equal(t, models.OrderStatusActorScheduler, history.Actor)
+	require.Equal(t, "Shipment declined", history.Reason)
+
+	// Cancellation requested by user is recorded on their behalf
+	userID := uuid.New()
+	history = stageHistory(value, models.OrderStatusPackaged, &models.OrderSaga{
+		Failure:       "Cancelled by user",
+		CancelActor:   models.OrderStatusActorUser,
+		CancelActorID: &userID,
+		CancelReason:  "changed my mind",
+	})
+	require.Equal(t, models.OrderStatusActorUser, history.Actor)
+	require.Equal(t, &userID, history.ActorID)
+	require.Equal(t, "changed my mind", history.Reason)
+	require.Equal(t, models.OrderStatusPackaged, history.FromStatus)
+	require.Equal(t, models.OrderStatusCancelled, history.ToStatus)
+}
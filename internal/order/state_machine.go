@@ -0,0 +1,10 @@
+package order
+
+import "github.com/engineerXIII/maiSystemBackend/internal/models"
+
+// Order status state machine
+type StateMachine interface {
+	CanTransition(from, to models.OrderStatus) bool
+	Transition(order *models.Order, to models.OrderStatus) error
+	Next(order *models.Order) error
+}
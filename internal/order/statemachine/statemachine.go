@@ -0,0 +1,67 @@
+package statemachine
+
+import (
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/pkg/errors"
+)
+
+// Allowed order status transitions, completed and cancelled orders are final
+var transitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusCreated:    {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+	models.OrderStatusConfirmed:  {models.OrderStatusPackaged, models.OrderStatusCancelled},
+	models.OrderStatusPackaged:   {models.OrderStatusInDelivery, models.OrderStatusCancelled},
+	models.OrderStatusInDelivery: {models.OrderStatusCompleted},
+}
+
+// Regular order processing flow without cancellation
+var forward = map[models.OrderStatus]models.OrderStatus{
+	models.OrderStatusCreated:    models.OrderStatusConfirmed,
+	models.OrderStatusConfirmed:  models.OrderStatusPackaged,
+	models.OrderStatusPackaged:   models.OrderStatusInDelivery,
+	models.OrderStatusInDelivery: models.OrderStatusCompleted,
+}
+
+type orderStateMachine struct{}
+
+// Order state machine constructor
+func NewOrderStateMachine() order.StateMachine {
+	return &orderStateMachine{}
+}
+
+// Check if order can be moved from one status to another
+func (m *orderStateMachine) CanTransition(from, to models.OrderStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Move order to given status, returns conflict error for illegal transitions
+func (m *orderStateMachine) Transition(order *models.Order, to models.OrderStatus) error {
+	if !m.CanTransition(order.Status, to) {
+		return httpErrors.NewConflictError(errors.Errorf(
+			"order status transition %s -> %s is not allowed",
+			order.Status.ToString(),
+			to.ToString(),
+		))
+	}
+	order.Status = to
+	order.StatusMessage = to.ToString()
+	return nil
+}
+
+// Move order to the next status of regular processing flow
+func (m *orderStateMachine) Next(order *models.Order) error {
+	next, ok := forward[order.Status]
+	if !ok {
+		return httpErrors.NewConflictError(errors.Errorf(
+			"order in status %s can not be processed further",
+			order.Status.ToString(),
+		))
+	}
+	return m.Transition(order, next)
+}
@@ -0,0 +1,56 @@
+package statemachine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+)
+
+func TestOrderStateMachine_Transition(t *testing.T) {
+	t.Parallel()
+
+	sm := NewOrderStateMachine()
+
+	t.Run("Allowed", func(t *testing.T) {
+		order := &models.Order{Status: models.OrderStatusCreated}
+		require.NoError(t, sm.Transition(order, models.OrderStatusCancelled))
+		require.Equal(t, models.OrderStatusCancelled, order.Status)
+		require.Equal(t, models.OrderStatusCancelled.ToString(), order.StatusMessage)
+	})
+
+	t.Run("SkipStatus", func(t *testing.T) {
+		order := &models.Order{Status: models.OrderStatusCreated}
+		err := sm.Transition(order, models.OrderStatusCompleted)
+		require.Error(t, err)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+		require.Equal(t, models.OrderStatusCreated, order.Status)
+	})
+
+	t.Run("FromFinal", func(t *testing.T) {
+		order := &models.Order{Status: models.OrderStatusCancelled}
+		require.Error(t, sm.Transition(order, models.OrderStatusCreated))
+		require.Error(t, sm.Next(order))
+	})
+}
+
+func TestOrderStateMachine_Next(t *testing.T) {
+	t.Parallel()
+
+	sm := NewOrderStateMachine()
+	order := &models.Order{Status: models.OrderStatusCreated}
+
+	for _, status := range []models.OrderStatus{
+		models.OrderStatusConfirmed,
+		models.OrderStatusPackaged,
+		models.OrderStatusInDelivery,
+		models.OrderStatusCompleted,
+	} {
+		require.NoError(t, sm.Next(order))
+		require.Equal(t, status, order.Status)
+	}
+	require.Error(t, sm.Next(order))
+}
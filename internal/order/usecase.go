@@ -4,13 +4,17 @@ package order
 import (
 	"context"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	"github.com/google/uuid"
 )
 
-// Product use case
+// Order use case
 type UseCase interface {
 	Create(ctx context.Context, order *models.Order) (*models.Order, error)
 	Update(ctx context.Context, order *models.Order) (*models.Order, error)
 	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*models.Order, error)
 	Delete(ctx context.Context, orderID uuid.UUID) error
+	GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error)
+	GetMyOrders(ctx context.Context, pq *utils.PaginationQuery) (*models.OrderList, error)
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*models.OrderStatusHistory, error)
 }
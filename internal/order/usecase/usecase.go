@@ -2,16 +2,22 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"fmt"
 	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/inventory"
 	"github.com/engineerXIII/maiSystemBackend/internal/models"
 	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/internal/product"
 	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
 	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // Redis variables
@@ -20,75 +26,237 @@ const (
 	cacheDuration = 3600
 )
 
+// Stock reservation lifetime for a new order
+const reservationTTL = 15 * time.Minute
+
 type orderUC struct {
-	cfg       *config.Config
-	orderRepo order.RedisRepository
-	logger    logger.Logger
+	cfg             *config.Config
+	orderRepo       order.Repository
+	redisRepo       order.RedisRepository
+	stateMachine    order.StateMachine
+	locker          order.Locker
+	saga            order.Saga
+	queue           order.Queue
+	productClient   product.Client
+	inventoryClient inventory.Client
+	logger          logger.Logger
 }
 
-func NewOrderUseCase(cfg *config.Config, orderRepo order.RedisRepository, logger logger.Logger) order.UseCase {
-	return &orderUC{cfg: cfg, orderRepo: orderRepo, logger: logger}
+func NewOrderUseCase(cfg *config.Config, orderRepo order.Repository, redisRepo order.RedisRepository, stateMachine order.StateMachine, locker order.Locker, saga order.Saga, queue order.Queue, productClient product.Client, inventoryClient inventory.Client, logger logger.Logger) order.UseCase {
+	return &orderUC{cfg: cfg, orderRepo: orderRepo, redisRepo: redisRepo, stateMachine: stateMachine, locker: locker, saga: saga, queue: queue, productClient: productClient, inventoryClient: inventoryClient, logger: logger}
 }
 
 func (u *orderUC) Create(ctx context.Context, order *models.Order) (*models.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.Create")
 	defer span.Finish()
 
-	if err := utils.ValidateStruct(ctx, order); err != nil {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, httpErrors.NewUnauthorizedError(errors.WithMessage(err, "orderUC.Create.GetUserFromCtx"))
+	}
+
+	if err = utils.ValidateStruct(ctx, order); err != nil {
 		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "orderUC.Create.ValidateStruct"))
 	}
 
+	order.UserID = &user.UserID
+
+	if err = u.resolveItemPrices(ctx, order.OrderList); err != nil {
+		return nil, err
+	}
+
 	order.OrderId = uuid.New()
-	order.Status = 1
+	order.Status = models.OrderStatusCreated
 	order.StatusMessage = order.Status.ToString()
 	order.CalculateSum()
 
-	s, _ := json.Marshal(order)
-	u.logger.Debug(string(s))
-
-	redisID := basePrefix + order.OrderId.String()
+	if err = u.reserveItems(ctx, order); err != nil {
+		return nil, err
+	}
 
-	err := u.orderRepo.SetOrderCtx(ctx, redisID, cacheDuration, order)
+	createdOrder, err := u.orderRepo.Create(ctx, order, u.statusHistory(ctx, order, models.OrderStatusUndefined, order.StatusReason))
 	if err != nil {
+		// Stock is not held for order which was not saved
+		u.releaseReservation(ctx, order)
 		return nil, err
 	}
 
-	return order, err
+	if err = u.redisRepo.SetOrderCtx(ctx, u.GenerateOrderKey(createdOrder.OrderId), cacheDuration, createdOrder); err != nil {
+		u.logger.Errorf("orderUC.Create.SetOrderCtx: %s", err)
+	}
+
+	return createdOrder, nil
 }
 
 func (u *orderUC) Update(ctx context.Context, order *models.Order) (*models.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.Update")
 	defer span.Finish()
 
-	order.StatusMessage = order.Status.ToString()
-	order.CalculateSum()
+	if err := utils.ValidateStruct(ctx, order); err != nil {
+		return nil, httpErrors.NewBadRequestError(errors.WithMessage(err, "orderUC.Update.ValidateStruct"))
+	}
 
-	redisID := basePrefix + order.OrderId.String()
+	// Order is changed under the same lock and fence as automatic processing, so neither overwrites the other
+	lock, ctx, err := u.lockOrder(ctx, order.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	defer u.unlockOrder(lock, order.OrderId)
 
-	_, err := u.orderRepo.GetOrderByIDCtx(ctx, redisID)
+	existingOrder, err := u.orderRepo.GetOrderByID(ctx, order.OrderId)
 	if err != nil {
 		return nil, err
 	}
 
-	err = u.orderRepo.SetOrderCtx(ctx, redisID, cacheDuration, order)
+	if err = u.validateOrderOwner(ctx, existingOrder); err != nil {
+		return nil, err
+	}
+
+	if len(order.OrderList) > 0 {
+		if existingOrder.Status != models.OrderStatusCreated {
+			return nil, httpErrors.NewConflictError(errors.Errorf(
+				"order items can not be changed in status %s",
+				existingOrder.Status.ToString(),
+			))
+		}
+		if err = u.resolveItemPrices(ctx, order.OrderList); err != nil {
+			return nil, err
+		}
+		existingOrder.OrderList = order.OrderList
+		existingOrder.CalculateSum()
+	}
+
+	fromStatus := existingOrder.Status
+	if order.Status != models.OrderStatusUndefined && order.Status != existingOrder.Status {
+		if err = u.validateStatusChange(ctx, order.Status); err != nil {
+			return nil, err
+		}
+		if inFulfilment(existingOrder.Status) {
+			if order.Status != models.OrderStatusCancelled {
+				return nil, httpErrors.NewConflictError(errors.Errorf(
+					"order in status %s is moved forward by fulfilment only",
+					existingOrder.Status.ToString(),
+				))
+			}
+			return u.cancelFulfilment(ctx, existingOrder, order.StatusReason, lock.Token())
+		}
+		if err = u.stateMachine.Transition(existingOrder, order.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stock held for order has to match its items, previous reservation is released once order is saved
+	var replaced *models.Order
+	if len(order.OrderList) > 0 && existingOrder.Status == models.OrderStatusCreated {
+		replaced = &models.Order{OrderId: existingOrder.OrderId, ReservationID: existingOrder.ReservationID}
+		if err = u.reserveItems(ctx, existingOrder); err != nil {
+			return nil, err
+		}
+	}
+
+	var history *models.OrderStatusHistory
+	if existingOrder.Status != fromStatus {
+		history = u.statusHistory(ctx, existingOrder, fromStatus, order.StatusReason)
+	}
+
+	updatedOrder, err := u.orderRepo.UpdateFenced(ctx, existingOrder, history, lock.Token())
 	if err != nil {
+		if replaced != nil {
+			// Stock is not held for items which were not saved
+			u.releaseReservation(ctx, existingOrder)
+		}
 		return nil, err
 	}
+	if replaced != nil {
+		u.releaseReservation(ctx, replaced)
+	}
+
+	if updatedOrder.Status != fromStatus && updatedOrder.Status == models.OrderStatusCancelled {
+		u.releaseReservation(ctx, existingOrder)
+	}
 
-	return order, nil
+	if err = u.redisRepo.DeleteOrderCtx(ctx, u.GenerateOrderKey(order.OrderId)); err != nil {
+		u.logger.Errorf("orderUC.Update.DeleteOrderCtx: %s", err)
+	}
+
+	return updatedOrder, nil
+}
+
+// Order in fulfilment is cancelled by saga compensation, which returns taken stock and payment
+// before the scheduler cancels the order, so order is returned in its current status.
+// Scheduler records the cancellation in status history on behalf of the user from context
+func (u *orderUC) cancelFulfilment(ctx context.Context, value *models.Order, reason string, token int64) (*models.Order, error) {
+	cancellation := u.statusHistory(ctx, value, value.Status, reason)
+	cancellation.ToStatus = models.OrderStatusCancelled
+	if _, err := u.saga.Cancel(ctx, value, cancellation, token); err != nil {
+		return nil, errors.Wrap(err, "orderUC.cancelFulfilment.Cancel")
+	}
+
+	// Saga is compensated by the next run of order stage, order left unqueued is picked up by active orders sweep
+	if err := u.queue.Publish(ctx, &models.OrderTask{OrderId: value.OrderId, Status: value.Status}); err != nil {
+		u.logger.Errorf("orderUC.cancelFulfilment.Publish: order %s: %s", value.OrderId, err)
+	}
+
+	if err := u.redisRepo.DeleteOrderCtx(ctx, u.GenerateOrderKey(value.OrderId)); err != nil {
+		u.logger.Errorf("orderUC.cancelFulfilment.DeleteOrderCtx: %s", err)
+	}
+	return value, nil
+}
+
+// Order statuses in which order is fulfilled by saga
+func inFulfilment(status models.OrderStatus) bool {
+	return status == models.OrderStatusConfirmed || status == models.OrderStatusPackaged
+}
+
+// Lock order for change, order held by another owner is reported as conflict
+func (u *orderUC) lockOrder(ctx context.Context, orderID uuid.UUID) (order.Lock, context.Context, error) {
+	lock, lockCtx, err := u.locker.Lock(ctx, orderID)
+	if errors.Is(err, order.ErrLocked) {
+		return nil, nil, httpErrors.NewConflictError(errors.Errorf("order %s is being processed, try again later", orderID))
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, httpErrors.NewInternalServerError(errors.Wrap(err, "orderUC.lockOrder"))
+	}
+	return lock, lockCtx, nil
+}
+
+func (u *orderUC) unlockOrder(lock order.Lock, orderID uuid.UUID) {
+	if err := lock.Unlock(context.Background()); err != nil {
+		u.logger.Errorf("orderUC.unlockOrder: order %s: %s", orderID, err)
+	}
 }
 
 func (u *orderUC) GetOrderByID(ctx context.Context, orderUUID uuid.UUID) (*models.Order, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.GetOrderByID")
 	defer span.Finish()
 
-	redisID := basePrefix + orderUUID.String()
+	cachedOrder, err := u.redisRepo.GetOrderByIDCtx(ctx, u.GenerateOrderKey(orderUUID))
+	if err != nil {
+		u.logger.Errorf("orderUC.GetOrderByID.GetOrderByIDCtx: %s", err)
+	}
+	if cachedOrder != nil {
+		if err = u.validateOrderOwner(ctx, cachedOrder); err != nil {
+			return nil, err
+		}
+		return cachedOrder, nil
+	}
 
-	p, err := u.orderRepo.GetOrderByIDCtx(ctx, redisID)
+	p, err := u.orderRepo.GetOrderByID(ctx, orderUUID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = u.validateOrderOwner(ctx, p); err != nil {
+		return nil, err
+	}
+
+	if err = u.redisRepo.SetOrderCtx(ctx, u.GenerateOrderKey(orderUUID), cacheDuration, p); err != nil {
+		u.logger.Errorf("orderUC.GetOrderByID.SetOrderCtx: %s", err)
+	}
+
 	return p, nil
 }
 
@@ -96,16 +264,206 @@ func (u *orderUC) Delete(ctx context.Context, orderUUID uuid.UUID) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.Delete")
 	defer span.Finish()
 
-	redisID := basePrefix + orderUUID.String()
+	// Order is deleted under the same lock and fence as automatic processing, so it is not deleted mid stage
+	lock, ctx, err := u.lockOrder(ctx, orderUUID)
+	if err != nil {
+		return err
+	}
+	defer u.unlockOrder(lock, orderUUID)
 
-	_, err := u.orderRepo.GetOrderByIDCtx(ctx, redisID)
+	existingOrder, err := u.orderRepo.GetOrderByID(ctx, orderUUID)
 	if err != nil {
 		return err
 	}
 
-	if err := u.orderRepo.DeleteOrderCtx(ctx, redisID); err != nil {
+	if err = u.validateOrderOwner(ctx, existingOrder); err != nil {
+		return err
+	}
+
+	// Order in fulfilment has to be cancelled first, so saga compensation returns stock and payment
+	if existingOrder.Status != models.OrderStatusCreated && existingOrder.Status != models.OrderStatusCancelled {
+		return httpErrors.NewConflictError(errors.Errorf(
+			"order can not be deleted in status %s",
+			existingOrder.Status.ToString(),
+		))
+	}
+
+	if err = u.orderRepo.Delete(ctx, orderUUID, lock.Token()); err != nil {
 		return err
 	}
 
+	u.releaseReservation(ctx, existingOrder)
+
+	if err = u.redisRepo.DeleteOrderCtx(ctx, u.GenerateOrderKey(orderUUID)); err != nil {
+		u.logger.Errorf("orderUC.Delete.DeleteOrderCtx: %s", err)
+	}
+
+	return nil
+}
+
+func (u *orderUC) GetOrders(ctx context.Context, filter *models.OrderFilter, pq *utils.PaginationQuery) (*models.OrderList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.GetOrders")
+	defer span.Finish()
+
+	return u.orderRepo.GetOrders(ctx, filter, pq)
+}
+
+func (u *orderUC) GetMyOrders(ctx context.Context, pq *utils.PaginationQuery) (*models.OrderList, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.GetMyOrders")
+	defer span.Finish()
+
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, httpErrors.NewUnauthorizedError(errors.WithMessage(err, "orderUC.GetMyOrders.GetUserFromCtx"))
+	}
+
+	return u.orderRepo.GetOrders(ctx, &models.OrderFilter{UserID: &user.UserID}, pq)
+}
+
+func (u *orderUC) GetStatusHistory(ctx context.Context, orderUUID uuid.UUID) ([]*models.OrderStatusHistory, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderUC.GetStatusHistory")
+	defer span.Finish()
+
+	existingOrder, err := u.orderRepo.GetOrderByID(ctx, orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.validateOrderOwner(ctx, existingOrder); err != nil {
+		return nil, err
+	}
+
+	return u.orderRepo.GetStatusHistory(ctx, orderUUID)
+}
+
+func (u *orderUC) GenerateOrderKey(orderID uuid.UUID) string {
+	return basePrefix + orderID.String()
+}
+
+// Replace client supplied item costs with the current catalog prices
+func (u *orderUC) resolveItemPrices(ctx context.Context, items []*models.OrderItem) error {
+	for _, item := range items {
+		p, err := u.productClient.GetProductByID(ctx, item.ItemId)
+		if err != nil {
+			if errors.Is(err, httpErrors.NotFound) {
+				return httpErrors.NewBadRequestError(errors.Errorf("unknown product %s", item.ItemId))
+			}
+			return errors.Wrap(err, "orderUC.resolveItemPrices.GetProductByID")
+		}
+		item.Cost = p.Cost
+	}
+
 	return nil
 }
+
+// Hold stock for order items, fails listing short items when inventory can not cover the order
+func (u *orderUC) reserveItems(ctx context.Context, order *models.Order) error {
+	reservation := &models.InventoryReservation{
+		OrderID:   order.OrderId,
+		Items:     make([]*models.InventoryItem, 0, len(order.OrderList)),
+		ExpiresAt: time.Now().Add(reservationTTL),
+	}
+	byID := make(map[uuid.UUID]*models.InventoryItem, len(order.OrderList))
+	for _, item := range order.OrderList {
+		if reserved, ok := byID[item.ItemId]; ok {
+			reserved.Qty += item.Qty
+			continue
+		}
+		byID[item.ItemId] = &models.InventoryItem{UUID: item.ItemId, Qty: item.Qty}
+		reservation.Items = append(reservation.Items, byID[item.ItemId])
+	}
+
+	shortItems, err := u.inventoryClient.Reserve(ctx, reservation)
+	if err != nil {
+		return errors.Wrap(err, "orderUC.reserveItems.Reserve")
+	}
+	if len(shortItems) > 0 {
+		short := make([]string, 0, len(shortItems))
+		for _, item := range shortItems {
+			requested := 0
+			if reserved, ok := byID[item.UUID]; ok {
+				requested = reserved.Qty
+			}
+			short = append(short, fmt.Sprintf("%s (requested %d, available %d)", item.UUID, requested, item.Qty))
+		}
+		return httpErrors.NewRestErrorWithMessage(
+			http.StatusConflict,
+			fmt.Sprintf("%s: %s", httpErrors.NotEnoughStock.Error(), strings.Join(short, ", ")),
+			shortItems,
+		)
+	}
+
+	order.ReservationID = &reservation.ReservationID
+	return nil
+}
+
+// Give reserved stock back, reservation may be already committed or expired
+func (u *orderUC) releaseReservation(ctx context.Context, order *models.Order) {
+	if order.ReservationID == nil {
+		return
+	}
+
+	err := u.inventoryClient.ReleaseReservation(ctx, *order.ReservationID, order.OrderId)
+	if err != nil && !errors.Is(err, httpErrors.NotFound) {
+		u.logger.Errorf("orderUC.releaseReservation.ReleaseReservation: %s", err)
+	}
+}
+
+// Check that the user from context owns the order or is an admin
+func (u *orderUC) validateOrderOwner(ctx context.Context, order *models.Order) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return httpErrors.NewUnauthorizedError(errors.WithMessage(err, "orderUC.validateOrderOwner.GetUserFromCtx"))
+	}
+
+	if user.Role != nil && *user.Role == "admin" {
+		return nil
+	}
+
+	if order.UserID == nil || *order.UserID != user.UserID {
+		u.logger.Errorf(
+			"orderUC.validateOrderOwner, userID: %v, orderID: %v",
+			user.UserID.String(),
+			order.OrderId.String(),
+		)
+		return httpErrors.NewForbiddenError(httpErrors.Forbidden)
+	}
+
+	return nil
+}
+
+// Owners may only cancel their orders, moving orders forward is left to admins and the scheduler
+func (u *orderUC) validateStatusChange(ctx context.Context, to models.OrderStatus) error {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return httpErrors.NewUnauthorizedError(errors.WithMessage(err, "orderUC.validateStatusChange.GetUserFromCtx"))
+	}
+
+	if user.Role != nil && *user.Role == "admin" {
+		return nil
+	}
+
+	if to != models.OrderStatusCancelled {
+		return httpErrors.NewForbiddenError(errors.Errorf("order status can not be changed to %s", to.ToString()))
+	}
+
+	return nil
+}
+
+// Status transition of the order made by the user from context
+func (u *orderUC) statusHistory(ctx context.Context, order *models.Order, from models.OrderStatus, reason string) *models.OrderStatusHistory {
+	history := &models.OrderStatusHistory{
+		OrderId:    order.OrderId,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Actor:      models.OrderStatusActorUser,
+		Reason:     reason,
+	}
+	if user, err := utils.GetUserFromCtx(ctx); err == nil {
+		history.ActorID = &user.UserID
+		if user.Role != nil && *user.Role == "admin" {
+			history.Actor = models.OrderStatusActorAdmin
+		}
+	}
+	return history
+}
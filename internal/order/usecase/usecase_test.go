@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	inventoryMock "github.com/engineerXIII/maiSystemBackend/internal/inventory/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/order"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/mock"
+	"github.com/engineerXIII/maiSystemBackend/internal/order/statemachine"
+	productMock "github.com/engineerXIII/maiSystemBackend/internal/product/mock"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+)
+
+func newTestLogger() logger.Logger {
+	cfg := &config.Config{
+		Logger: config.Logger{
+			Development:       true,
+			DisableCaller:     false,
+			DisableStacktrace: false,
+			Encoding:          "json",
+		},
+	}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	return apiLogger
+}
+
+func TestOrderUC_Create(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	mockInventoryClient := inventoryMock.NewMockClient(ctrl)
+	orderUC := NewOrderUseCase(&config.Config{}, mockOrderRepo, mockRedisRepo, statemachine.NewOrderStateMachine(), nil, nil, nil, mockProductClient, mockInventoryClient, newTestLogger())
+
+	user := &models.User{UserID: uuid.New()}
+	ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, user)
+
+	spoofedID := uuid.New()
+	item := &models.OrderItem{ItemId: uuid.New(), Cost: 1, Qty: 2}
+	order := &models.Order{
+		UserID:    &spoofedID,
+		OrderList: []*models.OrderItem{item},
+	}
+
+	reservationID := uuid.New()
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), item.ItemId).Return(&models.Product{ProductID: item.ItemId, Cost: 10}, nil)
+	mockInventoryClient.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, r *models.InventoryReservation) ([]*models.InventoryItem, error) {
+			require.Len(t, r.Items, 1)
+			require.Equal(t, item.Qty, r.Items[0].Qty)
+			r.ReservationID = reservationID
+			return nil, nil
+		})
+	mockOrderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, o *models.Order, h *models.OrderStatusHistory) (*models.Order, error) {
+			require.Equal(t, o.OrderId, h.OrderId)
+			require.Equal(t, models.OrderStatusCreated, h.ToStatus)
+			require.Equal(t, user.UserID, *h.ActorID)
+			return o, nil
+		})
+	mockRedisRepo.EXPECT().SetOrderCtx(gomock.Any(), gomock.Any(), cacheDuration, gomock.Any()).Return(nil)
+
+	createdOrder, err := orderUC.Create(ctx, order)
+	require.NoError(t, err)
+	require.Equal(t, user.UserID, *createdOrder.UserID)
+	require.Equal(t, 20, createdOrder.Sum)
+	require.Equal(t, reservationID, *createdOrder.ReservationID)
+
+	_, err = orderUC.Create(context.Background(), &models.Order{})
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, httpErrors.ParseErrors(err).Status())
+
+	// Item quantities are validated before products and stock are looked up
+	_, err = orderUC.Create(ctx, &models.Order{OrderList: []*models.OrderItem{{ItemId: uuid.New(), Qty: 0}}})
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, httpErrors.ParseErrors(err).Status())
+
+	unknownItem := &models.OrderItem{ItemId: uuid.New(), Qty: 1}
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), unknownItem.ItemId).Return(nil, errors.Wrap(httpErrors.NotFound, "GetProductByID"))
+
+	_, err = orderUC.Create(ctx, &models.Order{OrderList: []*models.OrderItem{unknownItem}})
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, httpErrors.ParseErrors(err).Status())
+
+	shortItem := &models.OrderItem{ItemId: uuid.New(), Qty: 5}
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), shortItem.ItemId).Return(&models.Product{ProductID: shortItem.ItemId, Cost: 10}, nil)
+	mockInventoryClient.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return([]*models.InventoryItem{{UUID: shortItem.ItemId, Qty: 2}}, nil)
+
+	_, err = orderUC.Create(ctx, &models.Order{OrderList: []*models.OrderItem{shortItem}})
+	require.Error(t, err)
+	restErr := httpErrors.ParseErrors(err)
+	require.Equal(t, http.StatusConflict, restErr.Status())
+	require.Contains(t, restErr.Error(), shortItem.ItemId.String())
+
+	mockProductClient.EXPECT().GetProductByID(gomock.Any(), item.ItemId).Return(&models.Product{ProductID: item.ItemId, Cost: 10}, nil)
+	mockInventoryClient.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, r *models.InventoryReservation) ([]*models.InventoryItem, error) {
+			r.ReservationID = reservationID
+			return nil, nil
+		})
+	mockOrderRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+	mockInventoryClient.EXPECT().ReleaseReservation(gomock.Any(), reservationID, gomock.Any()).Return(nil)
+
+	_, err = orderUC.Create(ctx, &models.Order{OrderList: []*models.OrderItem{item}})
+	require.Error(t, err)
+}
+
+func TestOrderUC_GetOrderByID(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockLocker := mock.NewMockLocker(ctrl)
+	mockSaga := mock.NewMockSaga(ctrl)
+	mockQueue := mock.NewMockQueue(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	mockInventoryClient := inventoryMock.NewMockClient(ctrl)
+	orderUC := NewOrderUseCase(&config.Config{}, mockOrderRepo, mockRedisRepo, statemachine.NewOrderStateMachine(), mockLocker, mockSaga, mockQueue, mockProductClient, mockInventoryClient, newTestLogger())
+
+	ownerID := uuid.New()
+	adminRole := "admin"
+	order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated}
+
+	mockRedisRepo.EXPECT().GetOrderByIDCtx(gomock.Any(), basePrefix+order.OrderId.String()).Return(order, nil).Times(3)
+
+	owner := &models.User{UserID: ownerID}
+	result, err := orderUC.GetOrderByID(context.WithValue(context.Background(), utils.UserCtxKey{}, owner), order.OrderId)
+	require.NoError(t, err)
+	require.Equal(t, order.OrderId, result.OrderId)
+
+	stranger := &models.User{UserID: uuid.New()}
+	_, err = orderUC.GetOrderByID(context.WithValue(context.Background(), utils.UserCtxKey{}, stranger), order.OrderId)
+	require.Error(t, err)
+	require.Equal(t, http.StatusForbidden, httpErrors.ParseErrors(err).Status())
+
+	admin := &models.User{UserID: uuid.New(), Role: &adminRole}
+	result, err = orderUC.GetOrderByID(context.WithValue(context.Background(), utils.UserCtxKey{}, admin), order.OrderId)
+	require.NoError(t, err)
+	require.Equal(t, order.OrderId, result.OrderId)
+}
+
+func TestOrderUC_Update(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockLocker := mock.NewMockLocker(ctrl)
+	mockSaga := mock.NewMockSaga(ctrl)
+	mockQueue := mock.NewMockQueue(ctrl)
+	mockProductClient := productMock.NewMockClient(ctrl)
+	mockInventoryClient := inventoryMock.NewMockClient(ctrl)
+	orderUC := NewOrderUseCase(&config.Config{}, mockOrderRepo, mockRedisRepo, statemachine.NewOrderStateMachine(), mockLocker, mockSaga, mockQueue, mockProductClient, mockInventoryClient, newTestLogger())
+
+	ownerID := uuid.New()
+	owner := &models.User{UserID: ownerID}
+	ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, owner)
+
+	expectLock := func(orderID uuid.UUID, token int64) {
+		mockLock := mock.NewMockLock(ctrl)
+		mockLocker.EXPECT().Lock(gomock.Any(), orderID).DoAndReturn(func(ctx context.Context, orderID uuid.UUID) (order.Lock, context.Context, error) {
+			return mockLock, ctx, nil
+		})
+		mockLock.EXPECT().Token().Return(token).AnyTimes()
+		mockLock.EXPECT().Unlock(gomock.Any()).Return(nil)
+	}
+
+	t.Run("OwnerCanNotMoveForward", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated}
+		expectLock(order.OrderId, 1)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: order.OrderId, Status: models.OrderStatusConfirmed})
+		require.Error(t, err)
+		require.Equal(t, http.StatusForbidden, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("OwnerCancels", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated}
+		expectLock(order.OrderId, 7)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+		mockOrderRepo.EXPECT().UpdateFenced(gomock.Any(), order, gomock.Any(), int64(7)).DoAndReturn(
+			func(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, token int64) (*models.Order, error) {
+				require.Equal(t, models.OrderStatusCreated, history.FromStatus)
+				require.Equal(t, models.OrderStatusCancelled, history.ToStatus)
+				return order, nil
+			})
+		mockRedisRepo.EXPECT().DeleteOrderCtx(gomock.Any(), gomock.Any()).Return(nil)
+
+		updated, err := orderUC.Update(ctx, &models.Order{OrderId: order.OrderId, Status: models.OrderStatusCancelled})
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusCancelled, updated.Status)
+	})
+
+	t.Run("OwnerChangesItems", func(t *testing.T) {
+		previousID, reservationID := uuid.New(), uuid.New()
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated, ReservationID: &previousID}
+		item := &models.OrderItem{ItemId: uuid.New(), Qty: 3}
+		expectLock(order.OrderId, 7)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+		mockProductClient.EXPECT().GetProductByID(gomock.Any(), item.ItemId).Return(&models.Product{ProductID: item.ItemId, Cost: 10}, nil)
+		mockInventoryClient.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, r *models.InventoryReservation) ([]*models.InventoryItem, error) {
+				require.Equal(t, []*models.InventoryItem{{UUID: item.ItemId, Qty: 3}}, r.Items)
+				r.ReservationID = reservationID
+				return nil, nil
+			})
+		mockOrderRepo.EXPECT().UpdateFenced(gomock.Any(), order, nil, int64(7)).DoAndReturn(
+			func(ctx context.Context, order *models.Order, history *models.OrderStatusHistory, token int64) (*models.Order, error) {
+				require.Equal(t, reservationID, *order.ReservationID)
+				require.Equal(t, 30, order.Sum)
+				return order, nil
+			})
+		// Stock held for previous items is given back once new items are saved
+		mockInventoryClient.EXPECT().ReleaseReservation(gomock.Any(), previousID, order.OrderId).Return(nil)
+		mockRedisRepo.EXPECT().DeleteOrderCtx(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: order.OrderId, OrderList: []*models.OrderItem{item}})
+		require.NoError(t, err)
+	})
+
+	t.Run("ItemQtyNotPositive", func(t *testing.T) {
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: uuid.New(), OrderList: []*models.OrderItem{{ItemId: uuid.New(), Qty: -1}}})
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("OwnerCancelsInFulfilment", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusPackaged}
+		expectLock(order.OrderId, 7)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+		mockSaga.EXPECT().Cancel(gomock.Any(), order, gomock.Any(), int64(7)).DoAndReturn(
+			func(ctx context.Context, order *models.Order, cancellation *models.OrderStatusHistory, token int64) (*models.OrderSaga, error) {
+				require.Equal(t, models.OrderStatusActorUser, cancellation.Actor)
+				require.Equal(t, &ownerID, cancellation.ActorID)
+				require.Equal(t, models.OrderStatusCancelled, cancellation.ToStatus)
+				require.Equal(t, "changed my mind", cancellation.Reason)
+				return &models.OrderSaga{State: models.OrderSagaStateCompensating}, nil
+			})
+		mockQueue.EXPECT().Publish(gomock.Any(), &models.OrderTask{OrderId: order.OrderId, Status: models.OrderStatusPackaged}).Return(nil)
+		mockRedisRepo.EXPECT().DeleteOrderCtx(gomock.Any(), gomock.Any()).Return(nil)
+
+		// Order is cancelled by the scheduler once saga is compensated
+		updated, err := orderUC.Update(ctx, &models.Order{OrderId: order.OrderId, Status: models.OrderStatusCancelled, StatusReason: "changed my mind"})
+		require.NoError(t, err)
+		require.Equal(t, models.OrderStatusPackaged, updated.Status)
+	})
+
+	t.Run("AdminCanNotMoveFulfilmentForward", func(t *testing.T) {
+		role := "admin"
+		adminCtx := context.WithValue(context.Background(), utils.UserCtxKey{}, &models.User{UserID: uuid.New(), Role: &role})
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusConfirmed}
+		expectLock(order.OrderId, 7)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+
+		// Packaging is a saga step, skipping it would leave saga behind order status
+		_, err := orderUC.Update(adminCtx, &models.Order{OrderId: order.OrderId, Status: models.OrderStatusPackaged})
+		require.Error(t, err)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("LostFence", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated}
+		expectLock(order.OrderId, 7)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+		mockOrderRepo.EXPECT().UpdateFenced(gomock.Any(), order, gomock.Any(), int64(7)).
+			Return(nil, httpErrors.NewConflictError(errors.Errorf("orderRepo.UpdateFenced: order %s was changed by another process", order.OrderId)))
+
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: order.OrderId, Status: models.OrderStatusCancelled})
+		require.Error(t, err)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("LockFailed", func(t *testing.T) {
+		orderID := uuid.New()
+		mockLocker.EXPECT().Lock(gomock.Any(), orderID).Return(nil, nil, errors.Wrap(errors.New("connection refused"), "orderLocker.Lock.IssueFencingToken"))
+
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: orderID, Status: models.OrderStatusCancelled})
+		require.Error(t, err)
+		require.Equal(t, http.StatusInternalServerError, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		orderID := uuid.New()
+		mockLocker.EXPECT().Lock(gomock.Any(), orderID).Return(nil, nil, errors.Wrap(order.ErrLocked, "orderLocker.Lock"))
+
+		_, err := orderUC.Update(ctx, &models.Order{OrderId: orderID, Status: models.OrderStatusCancelled})
+		require.Error(t, err)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+	})
+}
+
+func TestOrderUC_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockRepository(ctrl)
+	mockRedisRepo := mock.NewMockRedisRepository(ctrl)
+	mockLocker := mock.NewMockLocker(ctrl)
+	orderUC := NewOrderUseCase(&config.Config{}, mockOrderRepo, mockRedisRepo, statemachine.NewOrderStateMachine(), mockLocker, nil, nil, nil, nil, newTestLogger())
+
+	ownerID := uuid.New()
+	ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, &models.User{UserID: ownerID})
+
+	expectLock := func(orderID uuid.UUID, token int64) {
+		mockLock := mock.NewMockLock(ctrl)
+		mockLocker.EXPECT().Lock(gomock.Any(), orderID).DoAndReturn(func(ctx context.Context, orderID uuid.UUID) (order.Lock, context.Context, error) {
+			return mockLock, ctx, nil
+		})
+		mockLock.EXPECT().Token().Return(token).AnyTimes()
+		mockLock.EXPECT().Unlock(gomock.Any()).Return(nil)
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusCreated}
+		expectLock(order.OrderId, 5)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+		mockOrderRepo.EXPECT().Delete(gomock.Any(), order.OrderId, int64(5)).Return(nil)
+		mockRedisRepo.EXPECT().DeleteOrderCtx(gomock.Any(), gomock.Any()).Return(nil)
+
+		require.NoError(t, orderUC.Delete(ctx, order.OrderId))
+	})
+
+	t.Run("InFulfilment", func(t *testing.T) {
+		order := &models.Order{OrderId: uuid.New(), UserID: &ownerID, Status: models.OrderStatusPackaged}
+		expectLock(order.OrderId, 5)
+		mockOrderRepo.EXPECT().GetOrderByID(gomock.Any(), order.OrderId).Return(order, nil)
+
+		err := orderUC.Delete(ctx, order.OrderId)
+		require.Error(t, err)
+		require.Equal(t, http.StatusConflict, httpErrors.ParseErrors(err).Status())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		orderID := uuid.New()
+		mockLocker.EXPECT().Lock(gomock.Any(), orderID).Return(nil, nil, errors.Wrap(sql.ErrNoRows, "orderLocker.Lock.IssueFencingToken"))
+
+		err := orderUC.Delete(ctx, orderID)
+		require.Error(t, err)
+		require.Equal(t, http.StatusNotFound, httpErrors.ParseErrors(err).Status())
+	})
+}
@@ -0,0 +1,13 @@
+//go:generate mockgen -source client.go -destination mock/client_mock.go -package mock
+package product
+
+import (
+	"context"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Product catalog client for other services
+type Client interface {
+	GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+}
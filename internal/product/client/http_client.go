@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/internal/product"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	getProductPath = "/api/v1/product/%s"
+	requestTimeout = 5 * time.Second
+)
+
+// Product service HTTP client
+type productClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Product client constructor
+func NewProductClient(cfg *config.Config) product.Client {
+	return &productClient{
+		baseURL:    strings.TrimRight(cfg.Service.Product, "/"),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Get product from catalog by id
+func (c *productClient) GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "productClient.GetProductByID")
+	defer span.Finish()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+fmt.Sprintf(getProductPath, productID.String()), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "productClient.GetProductByID.NewRequestWithContext")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if err = opentracing.GlobalTracer().Inject(
+		span.Context(),
+		opentracing.HTTPHeaders,
+		opentracing.HTTPHeadersCarrier(req.Header),
+	); err != nil {
+		span.LogKV("event", "inject", "error", err.Error())
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "productClient.GetProductByID.Do")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.Wrapf(httpErrors.NotFound, "productClient.GetProductByID, productID: %s", productID)
+	case resp.StatusCode != http.StatusOK:
+		return nil, errors.Errorf("productClient.GetProductByID, productID: %s, unexpected status: %d", productID, resp.StatusCode)
+	}
+
+	p := &models.Product{}
+	if err = json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return nil, errors.Wrap(err, "productClient.GetProductByID.Decode")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/models"
+	"github.com/engineerXIII/maiSystemBackend/pkg/httpErrors"
+)
+
+func TestProductClient_GetProductByID(t *testing.T) {
+	t.Parallel()
+
+	productID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/product/"+productID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&models.Product{ProductID: productID, Cost: 150})
+	}))
+	defer server.Close()
+
+	productClient := NewProductClient(&config.Config{Service: config.Service{Product: server.URL + "/"}})
+
+	p, err := productClient.GetProductByID(context.Background(), productID)
+	require.NoError(t, err)
+	require.Equal(t, productID, p.ProductID)
+	require.Equal(t, 150, p.Cost)
+
+	_, err = productClient.GetProductByID(context.Background(), uuid.New())
+	require.Error(t, err)
+	require.True(t, errors.Is(err, httpErrors.NotFound))
+}
@@ -5,19 +5,23 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	authRepository "github.com/engineerXIII/maiSystemBackend/internal/auth/repository"
+	authUseCase "github.com/engineerXIII/maiSystemBackend/internal/auth/usecase"
 	inventoryHandler "github.com/engineerXIII/maiSystemBackend/internal/inventory/delivery/grpc"
+	inventoryHttp "github.com/engineerXIII/maiSystemBackend/internal/inventory/delivery/http"
+	inventoryPublisher "github.com/engineerXIII/maiSystemBackend/internal/inventory/publisher"
 	inventoryRepository "github.com/engineerXIII/maiSystemBackend/internal/inventory/repository"
-	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
-	//authRepository "github.com/engineerXIII/maiSystemBackend/internal/auth/repository"
-	//authUseCase "github.com/engineerXIII/maiSystemBackend/internal/auth/usecase"
 	inventoryUsecase "github.com/engineerXIII/maiSystemBackend/internal/inventory/usecase"
 	apiMiddlewares "github.com/engineerXIII/maiSystemBackend/internal/middleware"
+	productClient "github.com/engineerXIII/maiSystemBackend/internal/product/client"
 	sessionRepository "github.com/engineerXIII/maiSystemBackend/internal/session/repository"
 	seccUseCase "github.com/engineerXIII/maiSystemBackend/internal/session/usecase"
 	"github.com/engineerXIII/maiSystemBackend/pkg/csrf"
 	"github.com/engineerXIII/maiSystemBackend/pkg/metric"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"google.golang.org/grpc/reflection"
 	"net/http"
 	"strings"
 )
@@ -35,14 +39,18 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 
 	// Init repositories
 	sRepo := sessionRepository.NewSessionRepository(s.redisClient, s.cfg)
-	iRepo := inventoryRepository.NewInventoryRedisRepo(s.redisClient)
-	////aRepo := authRepository.NewAuthRepository(s.db)
+	aRepo := authRepository.NewAuthRepository(s.db)
+	authRedisRepo := authRepository.NewAuthRedisRepo(s.redisClient)
+	iRepo := inventoryRepository.NewInventoryRepository(s.db)
+	iRedisRepo := inventoryRepository.NewInventoryRedisRepo(s.redisClient)
+	iPublisher := inventoryPublisher.NewInventoryPublisher(s.amqqChannel, s.amqpQueue)
+	productCl := productClient.NewProductClient(s.cfg)
 	//orderRedisRepo := orderRepository.NewOrderRedisRepo(s.redisClient)
-	//
-	//// Init useCases
-	////authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, authRedisRepo, s.logger)
+
+	// Init useCases
+	authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, authRedisRepo, s.logger)
 	sessUC := seccUseCase.NewSessionUseCase(sRepo, s.cfg)
-	inventoryUC := inventoryUsecase.NewInventoryUseCase(s.cfg, iRepo, s.logger)
+	inventoryUC := inventoryUsecase.NewInventoryUseCase(s.cfg, iRepo, iRedisRepo, iPublisher, productCl, s.logger)
 	//orderUC := orderUseCase.NewOrderUseCase(s.cfg, orderRedisRepo, s.logger)
 
 	// Init handlers
@@ -52,8 +60,9 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 	//orderScheduler := orderScheduler.NewOrderScheduler(s.cfg, s.amqqChannel, s.amqpQueue, &orderRedisRepo, s.logger)
 	//orderScheduler.MapCron(s.scheduler)
 	inventoryHandler := inventoryHandler.NewInventoryServer(s.cfg, inventoryUC, s.logger)
+	inventoryHandlers := inventoryHttp.NewInventoryHandlers(s.cfg, inventoryUC, s.logger)
 
-	mw := apiMiddlewares.NewMiddlewareManager(sessUC, nil, s.cfg, []string{"*"}, s.logger)
+	mw := apiMiddlewares.NewMiddlewareManager(sessUC, authUC, s.cfg, []string{"*"}, s.logger)
 
 	e.Use(mw.RequestLoggerMiddleware)
 
@@ -91,6 +100,13 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 	}
 
 	pb.RegisterInventoryServiceServer(s.grpcServer, inventoryHandler)
+	if s.cfg.GRPC.Reflection {
+		reflection.Register(s.grpcServer)
+	}
+
+	v1 := e.Group("/api/v1")
+	inventoryGroup := v1.Group("/inventory")
+	inventoryHttp.MapInventoryRoutes(inventoryGroup, inventoryHandlers, mw)
 
 	httpMux := http.NewServeMux()
 	httpMux.HandleFunc("/ping", func(writer http.ResponseWriter, request *http.Request) {
@@ -0,0 +1,49 @@
+package product
+
+import (
+	"context"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+const (
+	healthCheckInterval = 5 * time.Second
+	healthCheckTimeout  = 2 * time.Second
+)
+
+// Register gRPC health service, inventory is serving while Redis is reachable.
+// Redis is checked periodically until ctx is done
+func (s *Server) MapHealth(ctx context.Context) {
+	s.healthServer = health.NewServer()
+	healthpb.RegisterHealthServer(s.grpcServer, s.healthServer)
+
+	go func() {
+		s.checkHealth(ctx)
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkHealth(ctx)
+			}
+		}
+	}()
+}
+
+// Ping Redis and update serving status of server and inventory service
+func (s *Server) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err := s.redisClient.Ping(ctx).Err(); err != nil {
+		s.logger.Warnf("Health check: Redis is unreachable: %s", err)
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.healthServer.SetServingStatus("", servingStatus)
+	s.healthServer.SetServingStatus(pb.InventoryService_ServiceDesc.ServiceName, servingStatus)
+}
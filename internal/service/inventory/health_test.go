@@ -0,0 +1,57 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
+)
+
+func TestServer_MapHealth(t *testing.T) {
+	t.Parallel()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer redisClient.Close()
+
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	s := &Server{cfg: cfg, grpcServer: grpc.NewServer(), redisClient: redisClient, logger: apiLogger}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.MapHealth(ctx)
+
+	servingStatus := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := s.healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+
+	t.Run("Serving", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			return servingStatus(pb.InventoryService_ServiceDesc.ServiceName) == healthpb.HealthCheckResponse_SERVING
+		}, healthCheckTimeout, 10*time.Millisecond)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(""))
+	})
+
+	t.Run("RedisClosed", func(t *testing.T) {
+		mr.Close()
+		s.checkHealth(ctx)
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(""))
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(pb.InventoryService_ServiceDesc.ServiceName))
+	})
+}
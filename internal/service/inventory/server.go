@@ -2,13 +2,16 @@ package product
 
 import (
 	"context"
-	"crypto/tls"
 	"github.com/engineerXIII/maiSystemBackend/config"
+	grpcInterceptors "github.com/engineerXIII/maiSystemBackend/pkg/grpc"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/metric"
 	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	amqp "github.com/rabbitmq/amqp091-go"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -19,56 +22,61 @@ import (
 
 // Server struct
 type Server struct {
-	echo        *echo.Echo
-	cfg         *config.Config
-	grpcServer  *grpc.Server
-	redisClient *redis.Client
-	logger      logger.Logger
+	echo         *echo.Echo
+	cfg          *config.Config
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+	db           *sqlx.DB
+	amqqChannel  *amqp.Channel
+	amqpQueue    *amqp.Queue
+	redisClient  *redis.Client
+	logger       logger.Logger
 }
 
 // NewServer New Server constructor
-func NewServer(cfg *config.Config, redisClient *redis.Client, logger logger.Logger) *Server {
-	return &Server{echo: echo.New(), cfg: cfg, redisClient: redisClient, logger: logger}
+func NewServer(cfg *config.Config, db *sqlx.DB, amqqChannel *amqp.Channel, amqpQueue *amqp.Queue, redisClient *redis.Client, logger logger.Logger) *Server {
+	return &Server{echo: echo.New(), cfg: cfg, db: db, amqqChannel: amqqChannel, amqpQueue: amqpQueue, redisClient: redisClient, logger: logger}
 }
 
 const (
-	certFile       = "ssl/cert.crt"
-	keyFile        = "ssl/private.key"
 	maxHeaderBytes = 1 << 20
 	ctxTimeout     = 5
 )
 
 func (s *Server) Run() error {
-	grpcOpts := []grpc.ServerOption{}
-	if true {
-		serverCert, err := tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			return err
-		}
-		tlsConfig := &tls.Config{
-			Certificates: []tls.Certificate{serverCert},
-			ClientAuth:   tls.NoClientCert,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		//creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
-		if err != nil {
-			return err
-		}
-		grpcOpts = append(grpcOpts, grpc.Creds(creds))
+	if err := grpcInterceptors.CheckServerTLS(s.cfg.Server.SSL, s.cfg.GRPC); err != nil {
+		return err
+	}
+	grpcMetrics, err := metric.CreateGRPCMetrics(s.cfg.Metrics.ServiceName)
+	if err != nil {
+		return err
 	}
+	im := grpcInterceptors.NewInterceptorManager(s.logger, grpcMetrics)
+	grpcOpts := append(im.ServerOptions(), grpcInterceptors.ServerOptions(s.cfg.GRPC)...)
 
 	s.grpcServer = grpc.NewServer(grpcOpts...)
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
+	s.MapHealth(healthCtx)
 
 	if s.cfg.Server.SSL {
 		if err := s.MapHandlers(s.echo); err != nil {
 			return err
 		}
-		s.echo.Server.ReadTimeout = time.Second * s.cfg.Server.ReadTimeout
-		s.echo.Server.WriteTimeout = time.Second * s.cfg.Server.WriteTimeout
-		s.echo.Server.MaxHeaderBytes = maxHeaderBytes
+		tlsConfig, err := grpcInterceptors.NewServerTLSConfig(s.cfg.GRPC)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{
+			Addr:           s.cfg.Server.Port,
+			ReadTimeout:    time.Second * s.cfg.Server.ReadTimeout,
+			WriteTimeout:   time.Second * s.cfg.Server.WriteTimeout,
+			MaxHeaderBytes: maxHeaderBytes,
+			TLSConfig:      tlsConfig,
+		}
 		go func() {
 			s.logger.Infof("Server is listening on PORT: %s", s.cfg.Server.Port)
-			if err := s.echo.StartTLS(s.cfg.Server.Port, certFile, keyFile); err != nil {
+			if err := s.echo.StartServer(server); err != nil {
 				s.logger.Fatalf("Error starting TLS Server: ", err)
 			}
 		}()
@@ -86,6 +94,8 @@ func (s *Server) Run() error {
 		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 		<-quit
+		stopHealth()
+		s.healthServer.Shutdown()
 
 		ctx, shutdown := context.WithTimeout(context.Background(), ctxTimeout*time.Second)
 		defer shutdown()
@@ -129,6 +139,8 @@ func (s *Server) Run() error {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
+	stopHealth()
+	s.healthServer.Shutdown()
 
 	ctx, shutdown := context.WithTimeout(context.Background(), ctxTimeout*time.Second)
 	defer shutdown()
@@ -3,6 +3,7 @@ package product
 import (
 	"context"
 	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/internal/notification/consumer"
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
 	"github.com/go-co-op/gocron"
 	"github.com/labstack/echo/v4"
@@ -51,9 +52,12 @@ func (s *Server) Run() error {
 		s.logger.Fatalf("AMQP failed to register a consumer. Error: %s", err)
 	}
 
+	notificationConsumer := consumer.NewNotificationConsumer(s.cfg, s.logger)
 	go func() {
 		for message := range messages {
-			s.logger.Infof("AMQP received a message: %s", message.Body)
+			if err := notificationConsumer.HandleMessage(context.Background(), message); err != nil {
+				s.logger.Errorf("AMQP failed to handle a message: %s", err)
+			}
 		}
 	}()
 
@@ -1,26 +1,36 @@
 package product
 
 import (
+	"context"
 	"fmt"
 	"github.com/engineerXIII/maiSystemBackend/docs"
 	//authHttp "github.com/engineerXIII/maiSystemBackend/internal/auth/delivery/http"
-	orderHttp "github.com/engineerXIII/maiSystemBackend/internal/order/delivery/http"
-	//authRepository "github.com/engineerXIII/maiSystemBackend/internal/auth/repository"
-	//authUseCase "github.com/engineerXIII/maiSystemBackend/internal/auth/usecase"
+	authRepository "github.com/engineerXIII/maiSystemBackend/internal/auth/repository"
+	authUseCase "github.com/engineerXIII/maiSystemBackend/internal/auth/usecase"
+	inventoryClient "github.com/engineerXIII/maiSystemBackend/internal/inventory/client"
 	apiMiddlewares "github.com/engineerXIII/maiSystemBackend/internal/middleware"
+	orderHttp "github.com/engineerXIII/maiSystemBackend/internal/order/delivery/http"
+	orderGateway "github.com/engineerXIII/maiSystemBackend/internal/order/gateway"
+	orderQueue "github.com/engineerXIII/maiSystemBackend/internal/order/queue"
 	orderRepository "github.com/engineerXIII/maiSystemBackend/internal/order/repository"
+	orderSaga "github.com/engineerXIII/maiSystemBackend/internal/order/saga"
 	orderScheduler "github.com/engineerXIII/maiSystemBackend/internal/order/scheduler"
+	orderStateMachine "github.com/engineerXIII/maiSystemBackend/internal/order/statemachine"
 	orderUseCase "github.com/engineerXIII/maiSystemBackend/internal/order/usecase"
+	productClient "github.com/engineerXIII/maiSystemBackend/internal/product/client"
 	sessionRepository "github.com/engineerXIII/maiSystemBackend/internal/session/repository"
 	seccUseCase "github.com/engineerXIII/maiSystemBackend/internal/session/usecase"
 	"github.com/engineerXIII/maiSystemBackend/pkg/csrf"
+	grpcInterceptors "github.com/engineerXIII/maiSystemBackend/pkg/grpc"
 	"github.com/engineerXIII/maiSystemBackend/pkg/metric"
 	"github.com/engineerXIII/maiSystemBackend/pkg/utils"
+	pb "github.com/engineerXIII/maiSystemBackend/proto/api/v1"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"net/http"
 	"strings"
+	"time"
 )
 
 func (s *Server) MapHandlers(e *echo.Echo) error {
@@ -36,22 +46,47 @@ func (s *Server) MapHandlers(e *echo.Echo) error {
 
 	// Init repositories
 	sRepo := sessionRepository.NewSessionRepository(s.redisClient, s.cfg)
-	//aRepo := authRepository.NewAuthRepository(s.db)
+	aRepo := authRepository.NewAuthRepository(s.db)
+	authRedisRepo := authRepository.NewAuthRedisRepo(s.redisClient)
+	orderRepo := orderRepository.NewOrderRepository(s.db)
 	orderRedisRepo := orderRepository.NewOrderRedisRepo(s.redisClient)
 
+	orderSM := orderStateMachine.NewOrderStateMachine()
+	productCl := productClient.NewProductClient(s.cfg)
+
+	grpcMetrics, err := metric.CreateGRPCMetrics(s.cfg.Metrics.ServiceName)
+	if err != nil {
+		return err
+	}
+	im := grpcInterceptors.NewInterceptorManager(s.logger, grpcMetrics)
+	inventoryConn, err := inventoryClient.NewInventoryConn(s.cfg, im.DialOptions()...)
+	if err != nil {
+		s.logger.Fatalf("GRPC not connect: %v", err)
+	}
+	inventoryGrpcClient := pb.NewInventoryServiceClient(inventoryConn)
+	inventoryCl := inventoryClient.NewInventoryClient(inventoryGrpcClient)
+
 	// Init useCases
-	//authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, authRedisRepo, s.logger)
+	authUC := authUseCase.NewAuthUseCase(s.cfg, aRepo, authRedisRepo, s.logger)
 	sessUC := seccUseCase.NewSessionUseCase(sRepo, s.cfg)
-	orderUC := orderUseCase.NewOrderUseCase(s.cfg, orderRedisRepo, s.logger)
+	orderLocker := orderRepository.NewOrderLocker(s.redisClient, orderRepo, time.Second*time.Duration(s.cfg.Processing.LockTTL))
+	orderQueue := orderQueue.NewOrderQueue(s.cfg, s.amqqChannel)
+	paymentGateway := orderGateway.NewCashOnDeliveryGateway(s.logger)
+	shippingGateway := orderGateway.NewCourierGateway(s.logger)
+	orderSaga := orderSaga.NewOrderSaga(s.cfg, orderRepo, orderSM, inventoryGrpcClient, paymentGateway, shippingGateway, s.logger)
+	orderUC := orderUseCase.NewOrderUseCase(s.cfg, orderRepo, orderRedisRepo, orderSM, orderLocker, orderSaga, orderQueue, productCl, inventoryCl, s.logger)
 
 	// Init handlers
 	//authHandlers := authHttp.NewAuthHandlers(s.cfg, authUC, sessUC, s.logger)
 	orderHandlers := orderHttp.NewOrderHandlers(s.cfg, orderUC, s.logger)
 
-	orderScheduler := orderScheduler.NewOrderScheduler(s.cfg, s.amqqChannel, s.amqpQueue, &orderRedisRepo, s.logger)
+	orderScheduler := orderScheduler.NewOrderScheduler(s.cfg, s.amqqChannel, s.amqpQueue, orderRepo, orderRedisRepo, orderSM, orderQueue, orderLocker, orderSaga, s.logger)
 	orderScheduler.MapCron(s.scheduler)
+	if err = orderScheduler.Start(context.Background()); err != nil {
+		return err
+	}
 
-	mw := apiMiddlewares.NewMiddlewareManager(sessUC, nil, s.cfg, []string{"*"}, s.logger)
+	mw := apiMiddlewares.NewMiddlewareManager(sessUC, authUC, s.cfg, []string{"*"}, s.logger)
 
 	e.Use(mw.RequestLoggerMiddleware)
 
@@ -6,6 +6,7 @@ import (
 	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
 	"github.com/go-co-op/gocron"
 	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 type Server struct {
 	echo        *echo.Echo
 	cfg         *config.Config
+	db          *sqlx.DB
 	amqqChannel *amqp.Channel
 	redisClient *redis.Client
 	amqpQueue   *amqp.Queue
@@ -28,8 +30,8 @@ type Server struct {
 }
 
 // NewServer New Server constructor
-func NewServer(cfg *config.Config, amqqChannel *amqp.Channel, amqpQueue *amqp.Queue, redisClient *redis.Client, scheduler *gocron.Scheduler, logger logger.Logger) *Server {
-	return &Server{echo: echo.New(), cfg: cfg, amqqChannel: amqqChannel, amqpQueue: amqpQueue, redisClient: redisClient, scheduler: scheduler, logger: logger}
+func NewServer(cfg *config.Config, db *sqlx.DB, amqqChannel *amqp.Channel, amqpQueue *amqp.Queue, redisClient *redis.Client, scheduler *gocron.Scheduler, logger logger.Logger) *Server {
+	return &Server{echo: echo.New(), cfg: cfg, db: db, amqqChannel: amqqChannel, amqpQueue: amqpQueue, redisClient: redisClient, scheduler: scheduler, logger: logger}
 }
 
 const (
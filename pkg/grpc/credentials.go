@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Client transport credentials, client certificate is presented for mutual TLS when it is configured
+func NewClientCredentials(cfg config.GRPC) (credentials.TransportCredentials, error) {
+	if cfg.Insecure {
+		return insecure.NewCredentials(), nil
+	}
+
+	certPool, err := loadCertPool(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		RootCAs:    certPool,
+		ServerName: cfg.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "NewClientCredentials.LoadX509KeyPair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// Check server TLS agrees with gRPC transport settings, gRPC shares the port with REST API
+// so plain server can not serve TLS clients and TLS server can not serve insecure ones
+func CheckServerTLS(ssl bool, cfg config.GRPC) error {
+	if ssl == cfg.Insecure {
+		return errors.Errorf("server SSL is %v while gRPC insecure mode is %v", ssl, cfg.Insecure)
+	}
+	return nil
+}
+
+// Server TLS config, client certificates signed by CA are verified when given.
+// Server shares the port with REST API, so missing certificate is rejected per gRPC call by ServerOptions
+func NewServerTLSConfig(cfg config.GRPC) (*tls.Config, error) {
+	if cfg.Insecure {
+		return nil, errors.New("NewServerTLSConfig: gRPC is configured insecure")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewServerTLSConfig.LoadX509KeyPair")
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.RequireClientCert {
+		certPool, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = certPool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// Health checks are answered without client certificate, so probes need no service identity
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+// Server options rejecting calls without verified client certificate, empty in insecure mode
+func ServerOptions(cfg config.GRPC) []grpc.ServerOption {
+	if cfg.Insecure || !cfg.RequireClientCert {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := verifyClientCert(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := verifyClientCert(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// Check peer presented client certificate verified against CA
+func verifyClientCert(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, healthMethodPrefix) {
+		return nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "client certificate is required")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return status.Error(codes.Unauthenticated, "client certificate is required")
+	}
+	return nil
+}
+
+// Load CA certificates from PEM file
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pemCA, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loadCertPool.ReadFile")
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(pemCA) {
+		return nil, errors.New("failed to add CA certificate")
+	}
+	return certPool, nil
+}
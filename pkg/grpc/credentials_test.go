@@ -0,0 +1,227 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+)
+
+const testServerName = "inventory"
+
+// Certificate authority issuing test certificates into directory
+type testCA struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, "ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{dir: dir, cert: cert, key: key, file: file}
+}
+
+// Issue certificate signed by CA, returns certificate and key files
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(ca.dir, name+".crt"), filepath.Join(ca.dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
+
+func TestNewClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, t.TempDir())
+	certFile, keyFile := ca.issue(t, "order", x509.ExtKeyUsageClientAuth)
+
+	t.Run("Insecure", func(t *testing.T) {
+		creds, err := NewClientCredentials(config.GRPC{Insecure: true})
+		require.NoError(t, err)
+		require.Equal(t, "insecure", creds.Info().SecurityProtocol)
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		creds, err := NewClientCredentials(config.GRPC{CAFile: ca.file, CertFile: certFile, KeyFile: keyFile, ServerName: testServerName})
+		require.NoError(t, err)
+		require.Equal(t, "tls", creds.Info().SecurityProtocol)
+	})
+
+	t.Run("MissingCA", func(t *testing.T) {
+		_, err := NewClientCredentials(config.GRPC{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+		require.Error(t, err)
+	})
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, t.TempDir())
+	certFile, keyFile := ca.issue(t, testServerName, x509.ExtKeyUsageServerAuth)
+
+	t.Run("RequireClientCert", func(t *testing.T) {
+		tlsConfig, err := NewServerTLSConfig(config.GRPC{CAFile: ca.file, CertFile: certFile, KeyFile: keyFile, RequireClientCert: true})
+		require.NoError(t, err)
+		require.Len(t, tlsConfig.Certificates, 1)
+		require.NotNil(t, tlsConfig.ClientCAs)
+		require.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+	})
+
+	t.Run("Insecure", func(t *testing.T) {
+		_, err := NewServerTLSConfig(config.GRPC{Insecure: true, CertFile: certFile, KeyFile: keyFile})
+		require.Error(t, err)
+	})
+}
+
+func TestCheckServerTLS(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, CheckServerTLS(true, config.GRPC{}))
+	require.NoError(t, CheckServerTLS(false, config.GRPC{Insecure: true}))
+	require.Error(t, CheckServerTLS(false, config.GRPC{}))
+	require.Error(t, CheckServerTLS(true, config.GRPC{Insecure: true}))
+}
+
+func TestServerOptions_VerifyClientCert(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	serverCert, serverKey := ca.issue(t, testServerName, x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "order", x509.ExtKeyUsageClientAuth)
+	serverCfg := config.GRPC{CAFile: ca.file, CertFile: serverCert, KeyFile: serverKey, RequireClientCert: true}
+
+	tlsConfig, err := NewServerTLSConfig(serverCfg)
+	require.NoError(t, err)
+	health := &testHealthServer{check: func(ctx context.Context) (*grpc_health_v1.HealthCheckResponse, error) {
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	}}
+	// Health check under a name which is not exempt from client certificate check
+	protected := grpc.ServiceDesc{
+		ServiceName: "test.Protected",
+		HandlerType: (*grpc_health_v1.HealthServer)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Check",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := &grpc_health_v1.HealthCheckRequest{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(grpc_health_v1.HealthServer).Check(ctx, req.(*grpc_health_v1.HealthCheckRequest))
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Protected/Check"}, handler)
+			},
+		}},
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(append(ServerOptions(serverCfg), grpc.Creds(credentials.NewTLS(tlsConfig)))...)
+	grpc_health_v1.RegisterHealthServer(server, health)
+	server.RegisterService(&protected, health)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	dial := func(t *testing.T, cfg config.GRPC) *grpc.ClientConn {
+		creds, err := NewClientCredentials(cfg)
+		require.NoError(t, err)
+		conn, err := grpc.DialContext(context.Background(), "bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(creds),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	call := func(conn *grpc.ClientConn, method string) error {
+		return conn.Invoke(context.Background(), method, &grpc_health_v1.HealthCheckRequest{}, &grpc_health_v1.HealthCheckResponse{})
+	}
+
+	t.Run("NoClientCert", func(t *testing.T) {
+		conn := dial(t, config.GRPC{CAFile: ca.file, ServerName: testServerName})
+		err := call(conn, "/test.Protected/Check")
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("HealthWithoutClientCert", func(t *testing.T) {
+		conn := dial(t, config.GRPC{CAFile: ca.file, ServerName: testServerName})
+		require.NoError(t, call(conn, "/grpc.health.v1.Health/Check"))
+	})
+
+	t.Run("SignedClientCert", func(t *testing.T) {
+		conn := dial(t, config.GRPC{CAFile: ca.file, CertFile: clientCert, KeyFile: clientKey, ServerName: testServerName})
+		require.NoError(t, call(conn, "/test.Protected/Check"))
+	})
+
+	t.Run("ClientCertOfOtherCA", func(t *testing.T) {
+		other := newTestCA(t, t.TempDir())
+		otherCert, otherKey := other.issue(t, "order", x509.ExtKeyUsageClientAuth)
+		conn := dial(t, config.GRPC{CAFile: ca.file, CertFile: otherCert, KeyFile: otherKey, ServerName: testServerName})
+		require.Error(t, call(conn, "/test.Protected/Check"))
+	})
+}
@@ -0,0 +1,243 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+	"github.com/engineerXIII/maiSystemBackend/pkg/metric"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	sideServer = "server"
+	sideClient = "client"
+)
+
+// Interceptor manager, traces, measures and logs gRPC calls
+type InterceptorManager struct {
+	logger  logger.Logger
+	metrics metric.GRPCMetrics
+}
+
+// Interceptor manager constructor
+func NewInterceptorManager(logger logger.Logger, metrics metric.GRPCMetrics) *InterceptorManager {
+	return &InterceptorManager{logger: logger, metrics: metrics}
+}
+
+// Server options with unary and stream interceptors
+func (im *InterceptorManager) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(im.UnaryServerInterceptor),
+		grpc.StreamInterceptor(im.StreamServerInterceptor),
+	}
+}
+
+// Dial options with unary and stream interceptors
+func (im *InterceptorManager) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(im.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(im.StreamClientInterceptor),
+	}
+}
+
+// Unary server interceptor, continues trace of client and converts panics into internal status
+func (im *InterceptorManager) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	span, ctx := serverSpan(ctx, info.FullMethod)
+	defer span.Finish()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = im.recoverPanic(info.FullMethod, r)
+		}
+		im.observe(span, sideServer, info.FullMethod, start, err)
+	}()
+
+	return handler(ctx, req)
+}
+
+// Stream server interceptor, continues trace of client and converts panics into internal status
+func (im *InterceptorManager) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	span, ctx := serverSpan(ss.Context(), info.FullMethod)
+	defer span.Finish()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = im.recoverPanic(info.FullMethod, r)
+		}
+		im.observe(span, sideServer, info.FullMethod, start, err)
+	}()
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// Unary client interceptor, passes trace of the caller to server
+func (im *InterceptorManager) UnaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	span, ctx := clientSpan(ctx, method)
+	defer span.Finish()
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	im.observe(span, sideClient, method, start, err)
+	return err
+}
+
+// Stream client interceptor, passes trace of the caller to server, call is observed when stream ends
+func (im *InterceptorManager) StreamClientInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	span, ctx := clientSpan(ctx, method)
+
+	start := time.Now()
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		im.observe(span, sideClient, method, start, err)
+		span.Finish()
+		return nil, err
+	}
+
+	return &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams, finish: func(err error) {
+		im.observe(span, sideClient, method, start, err)
+		span.Finish()
+	}}, nil
+}
+
+// Record call outcome in span, metrics and log
+func (im *InterceptorManager) observe(span opentracing.Span, side, method string, start time.Time, err error) {
+	code := status.Code(err)
+	elapsed := time.Since(start)
+
+	im.metrics.ObserveResponseTime(side, method, code.String(), elapsed.Seconds())
+	im.metrics.IncHits(side, method, code.String())
+
+	span.SetTag("grpc.code", code.String())
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("event", "error", "message", err.Error())
+	}
+
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded:
+		im.logger.Errorf("gRPC %s, Method: %s, Code: %s, Time: %s, Error: %s", side, method, code, elapsed, err)
+	default:
+		im.logger.Infof("gRPC %s, Method: %s, Code: %s, Time: %s", side, method, code, elapsed)
+	}
+}
+
+// Log recovered panic with stack, client gets internal status only
+func (im *InterceptorManager) recoverPanic(method string, r interface{}) error {
+	im.logger.Errorf("gRPC panic, Method: %s, Panic: %v\n%s", method, r, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+// Start server span as child of span context sent by client
+func serverSpan(ctx context.Context, method string) (opentracing.Span, context.Context) {
+	tracer := opentracing.GlobalTracer()
+	md, _ := metadata.FromIncomingContext(ctx)
+	spanCtx, _ := tracer.Extract(opentracing.TextMap, metadataCarrier(md))
+
+	span := tracer.StartSpan(method, ext.RPCServerOption(spanCtx), ext.SpanKindRPCServer)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// Start client span and inject its context into outgoing metadata
+func clientSpan(ctx context.Context, method string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, method, ext.SpanKindRPCClient)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	if err := span.Tracer().Inject(span.Context(), opentracing.TextMap, metadataCarrier(md)); err != nil {
+		span.LogKV("event", "inject", "error", err.Error())
+	}
+	return span, metadata.NewOutgoingContext(ctx, md)
+}
+
+// Server stream carrying traced context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// Client stream finishing observation once the stream ends
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	once          sync.Once
+	finish        func(err error)
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && err != io.EOF {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.done(nil)
+	case err != nil:
+		s.done(err)
+	case !s.serverStreams:
+		// Single response of client streaming call ends the stream
+		s.done(nil)
+	}
+	return err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) done(err error) {
+	s.once.Do(func() {
+		s.finish(err)
+	})
+}
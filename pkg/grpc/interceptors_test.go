@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/engineerXIII/maiSystemBackend/config"
+	"github.com/engineerXIII/maiSystemBackend/pkg/logger"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+)
+
+// Metrics counting hits by side, method and code
+type testMetrics struct {
+	mu   sync.Mutex
+	hits map[[3]string]int
+}
+
+func (m *testMetrics) IncHits(side, method, code string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits[[3]string{side, method, code}]++
+}
+
+func (m *testMetrics) ObserveResponseTime(side, method, code string, observeTime float64) {}
+
+func (m *testMetrics) Hits(side, method, code string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits[[3]string{side, method, code}]
+}
+
+// Health server with replaceable handlers
+type testHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	check func(ctx context.Context) (*grpc_health_v1.HealthCheckResponse, error)
+	watch func(stream grpc_health_v1.Health_WatchServer) error
+}
+
+func (s *testHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return s.check(ctx)
+}
+
+func (s *testHealthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	return s.watch(stream)
+}
+
+// Use mock tracer as global tracer for a test
+func newTestTracer(t *testing.T) *mocktracer.MockTracer {
+	tracer := mocktracer.New()
+	previous := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() { opentracing.SetGlobalTracer(previous) })
+	return tracer
+}
+
+// Serve health server over in-memory connection with interceptors on both sides
+func newTestHealthClient(t *testing.T, health grpc_health_v1.HealthServer) (grpc_health_v1.HealthClient, *testMetrics) {
+	cfg := &config.Config{}
+	apiLogger := logger.NewApiLogger(cfg)
+	apiLogger.InitLogger()
+	metrics := &testMetrics{hits: make(map[[3]string]int)}
+	im := NewInterceptorManager(apiLogger, metrics)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(im.ServerOptions()...)
+	grpc_health_v1.RegisterHealthServer(server, health)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	opts := append(im.DialOptions(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.DialContext(context.Background(), "bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn), metrics
+}
+
+func TestInterceptorManager_UnaryServerInterceptor(t *testing.T) {
+	newTestTracer(t)
+
+	t.Run("Panic", func(t *testing.T) {
+		client, metrics := newTestHealthClient(t, &testHealthServer{
+			check: func(ctx context.Context) (*grpc_health_v1.HealthCheckResponse, error) {
+				panic("boom")
+			},
+		})
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+		require.NotContains(t, err.Error(), "boom")
+		require.Equal(t, 1, metrics.Hits(sideServer, checkMethod, codes.Internal.String()))
+		require.Equal(t, 1, metrics.Hits(sideClient, checkMethod, codes.Internal.String()))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client, metrics := newTestHealthClient(t, &testHealthServer{
+			check: func(ctx context.Context) (*grpc_health_v1.HealthCheckResponse, error) {
+				return nil, status.Error(codes.NotFound, "unknown service")
+			},
+		})
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		require.Equal(t, 1, metrics.Hits(sideServer, checkMethod, codes.NotFound.String()))
+	})
+}
+
+func TestInterceptorManager_SpanContext(t *testing.T) {
+	tracer := newTestTracer(t)
+
+	var serverSpan *mocktracer.MockSpan
+	client, _ := newTestHealthClient(t, &testHealthServer{
+		check: func(ctx context.Context) (*grpc_health_v1.HealthCheckResponse, error) {
+			serverSpan = opentracing.SpanFromContext(ctx).(*mocktracer.MockSpan)
+			return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+		},
+	})
+
+	parent, ctx := opentracing.StartSpanFromContext(context.Background(), "caller")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	parent.Finish()
+
+	require.NotNil(t, serverSpan)
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 3)
+	var clientSpan *mocktracer.MockSpan
+	for _, span := range spans {
+		if span.Tag(string(ext.SpanKind)) == ext.SpanKindRPCClientEnum {
+			clientSpan = span
+		}
+	}
+	require.NotNil(t, clientSpan)
+	parentCtx := parent.Context().(mocktracer.MockSpanContext)
+	require.Equal(t, parentCtx.SpanID, clientSpan.ParentID)
+	require.Equal(t, parentCtx.TraceID, serverSpan.SpanContext.TraceID)
+	require.Equal(t, clientSpan.SpanContext.SpanID, serverSpan.ParentID)
+	require.Equal(t, codes.OK.String(), serverSpan.Tag("grpc.code"))
+}
+
+func TestMetadataCarrier(t *testing.T) {
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("caller")
+	span.SetBaggageItem("user", "admin")
+
+	md := metadata.MD{}
+	require.NoError(t, tracer.Inject(span.Context(), opentracing.TextMap, metadataCarrier(md)))
+	require.NotEmpty(t, md)
+	for key := range md {
+		require.Equal(t, strings.ToLower(key), key)
+	}
+
+	spanCtx, err := tracer.Extract(opentracing.TextMap, metadataCarrier(md))
+	require.NoError(t, err)
+	extracted := spanCtx.(mocktracer.MockSpanContext)
+	injected := span.Context().(mocktracer.MockSpanContext)
+	require.Equal(t, injected.TraceID, extracted.TraceID)
+	require.Equal(t, injected.SpanID, extracted.SpanID)
+	require.Equal(t, "admin", extracted.Baggage["user"])
+
+	carrier := metadataCarrier(metadata.MD{})
+	carrier.Set("Uber-Trace-Id", "1:2:0:1")
+	require.Equal(t, []string{"1:2:0:1"}, metadata.MD(carrier)["uber-trace-id"])
+}
+
+func TestInterceptorManager_StreamClientInterceptor(t *testing.T) {
+	tracer := newTestTracer(t)
+
+	client, metrics := newTestHealthClient(t, &testHealthServer{
+		watch: func(stream grpc_health_v1.Health_WatchServer) error {
+			for _, s := range []grpc_health_v1.HealthCheckResponse_ServingStatus{
+				grpc_health_v1.HealthCheckResponse_SERVING,
+				grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			} {
+				if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: s}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, 0, metrics.Hits(sideClient, watchMethod, codes.OK.String()))
+	}
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 1, metrics.Hits(sideClient, watchMethod, codes.OK.String()))
+
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 1, metrics.Hits(sideClient, watchMethod, codes.OK.String()))
+
+	var clientSpans int
+	for _, span := range tracer.FinishedSpans() {
+		if span.Tag(string(ext.SpanKind)) == ext.SpanKindRPCClientEnum {
+			clientSpans++
+			require.Equal(t, codes.OK.String(), span.Tag("grpc.code"))
+		}
+	}
+	require.Equal(t, 1, clientSpans)
+}
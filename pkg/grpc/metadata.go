@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// gRPC metadata carrier of opentracing span context
+type metadataCarrier metadata.MD
+
+// Set implements opentracing.TextMapWriter, metadata keys are lowercase
+func (c metadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+// ForeachKey implements opentracing.TextMapReader
+func (c metadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for key, values := range c {
+		for _, value := range values {
+			if err := handler(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
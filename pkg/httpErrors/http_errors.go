@@ -18,6 +18,8 @@ const (
 	ErrNotFound           = "Not Found"
 	ErrUnauthorized       = "Unauthorized"
 	ErrForbidden          = "Forbidden"
+	ErrConflict           = "Conflict"
+	ErrNotEnoughStock     = "Not enough stock"
 	ErrBadQueryParams     = "Invalid query params"
 )
 
@@ -27,6 +29,8 @@ var (
 	NotFound              = errors.New("Not Found")
 	Unauthorized          = errors.New("Unauthorized")
 	Forbidden             = errors.New("Forbidden")
+	Conflict              = errors.New("Conflict")
+	NotEnoughStock        = errors.New("Not enough stock")
 	PermissionDenied      = errors.New("Permission Denied")
 	ExpiredCSRFError      = errors.New("Expired CSRF token")
 	WrongCSRFToken        = errors.New("Wrong CSRF token")
@@ -134,6 +138,15 @@ func NewForbiddenError(causes interface{}) RestErr {
 	}
 }
 
+// New Conflict Error
+func NewConflictError(causes interface{}) RestErr {
+	return RestError{
+		ErrStatus: http.StatusConflict,
+		ErrError:  Conflict.Error(),
+		ErrCauses: causes,
+	}
+}
+
 // New Internal Server Error
 func NewInternalServerError(causes interface{}) RestErr {
 	result := RestError{
@@ -146,7 +159,10 @@ func NewInternalServerError(causes interface{}) RestErr {
 
 // Parser of error string messages returns RestError
 func ParseErrors(err error) RestErr {
+	var restErr RestErr
 	switch {
+	case errors.As(err, &restErr):
+		return restErr
 	case errors.Is(err, sql.ErrNoRows):
 		return NewRestError(http.StatusNotFound, NotFound.Error(), err)
 	case errors.Is(err, context.DeadlineExceeded):
@@ -166,9 +182,6 @@ func ParseErrors(err error) RestErr {
 	case strings.Contains(strings.ToLower(err.Error()), "bcrypt"):
 		return NewRestError(http.StatusUnauthorized, Unauthorized.Error(), err)
 	default:
-		if restErr, ok := err.(RestErr); ok {
-			return restErr
-		}
 		return NewInternalServerError(err)
 	}
 }
@@ -82,3 +82,53 @@ func (metr *PrometheusMetrics) IncHits(status int, method, path string) {
 func (metr *PrometheusMetrics) ObserveResponseTime(status int, method, path string, observeTime float64) {
 	metr.Times.WithLabelValues(strconv.Itoa(status), method, path).Observe(observeTime)
 }
+
+// gRPC Metrics interface
+type GRPCMetrics interface {
+	IncHits(side, method, code string)
+	ObserveResponseTime(side, method, code string, observeTime float64)
+}
+
+// Prometheus gRPC Metrics struct
+type PrometheusGRPCMetrics struct {
+	Hits  *prometheus.CounterVec
+	Times *prometheus.HistogramVec
+}
+
+// Create gRPC metrics with name, they are exposed by metrics server of CreateMetrics
+func CreateGRPCMetrics(name string) (GRPCMetrics, error) {
+	var metr PrometheusGRPCMetrics
+	metr.Hits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: name + "_grpc_hits",
+		},
+		[]string{"side", "method", "code"},
+	)
+
+	if err := prometheus.Register(metr.Hits); err != nil {
+		return nil, err
+	}
+
+	metr.Times = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: name + "_grpc_times",
+		},
+		[]string{"side", "method", "code"},
+	)
+
+	if err := prometheus.Register(metr.Times); err != nil {
+		return nil, err
+	}
+
+	return &metr, nil
+}
+
+// IncHits
+func (metr *PrometheusGRPCMetrics) IncHits(side, method, code string) {
+	metr.Hits.WithLabelValues(side, method, code).Inc()
+}
+
+// Observer response time
+func (metr *PrometheusGRPCMetrics) ObserveResponseTime(side, method, code string, observeTime float64) {
+	metr.Times.WithLabelValues(side, method, code).Observe(observeTime)
+}
@@ -19,9 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	InventoryService_CheckItem_FullMethodName  = "/InventoryService/CheckItem"
-	InventoryService_AddItem_FullMethodName    = "/InventoryService/AddItem"
-	InventoryService_RemoveItem_FullMethodName = "/InventoryService/RemoveItem"
+	InventoryService_CheckItem_FullMethodName          = "/InventoryService/CheckItem"
+	InventoryService_AddItem_FullMethodName            = "/InventoryService/AddItem"
+	InventoryService_RemoveItem_FullMethodName         = "/InventoryService/RemoveItem"
+	InventoryService_Reserve_FullMethodName            = "/InventoryService/Reserve"
+	InventoryService_CommitReservation_FullMethodName  = "/InventoryService/CommitReservation"
+	InventoryService_ReleaseReservation_FullMethodName = "/InventoryService/ReleaseReservation"
+	InventoryService_ExtendReservation_FullMethodName  = "/InventoryService/ExtendReservation"
+	InventoryService_Allocate_FullMethodName           = "/InventoryService/Allocate"
+	InventoryService_CreateWarehouse_FullMethodName    = "/InventoryService/CreateWarehouse"
+	InventoryService_UpdateWarehouse_FullMethodName    = "/InventoryService/UpdateWarehouse"
+	InventoryService_GetWarehouse_FullMethodName       = "/InventoryService/GetWarehouse"
+	InventoryService_ListWarehouses_FullMethodName     = "/InventoryService/ListWarehouses"
+	InventoryService_DeleteWarehouse_FullMethodName    = "/InventoryService/DeleteWarehouse"
+	InventoryService_ListMovements_FullMethodName      = "/InventoryService/ListMovements"
+	InventoryService_ReconcileStock_FullMethodName     = "/InventoryService/ReconcileStock"
+	InventoryService_WatchItems_FullMethodName         = "/InventoryService/WatchItems"
+	InventoryService_ImportItems_FullMethodName        = "/InventoryService/ImportItems"
+	InventoryService_ExportItems_FullMethodName        = "/InventoryService/ExportItems"
+	InventoryService_GetThreshold_FullMethodName       = "/InventoryService/GetThreshold"
+	InventoryService_SetThreshold_FullMethodName       = "/InventoryService/SetThreshold"
 )
 
 // InventoryServiceClient is the client API for InventoryService service.
@@ -31,6 +48,23 @@ type InventoryServiceClient interface {
 	CheckItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*ItemAvailableResponse, error)
 	AddItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Response, error)
 	RemoveItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Response, error)
+	Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error)
+	CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Response, error)
+	ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Response, error)
+	ExtendReservation(ctx context.Context, in *ExtendReservationRequest, opts ...grpc.CallOption) (*ReserveResponse, error)
+	Allocate(ctx context.Context, in *AllocateRequest, opts ...grpc.CallOption) (*AllocateResponse, error)
+	CreateWarehouse(ctx context.Context, in *Warehouse, opts ...grpc.CallOption) (*WarehouseResponse, error)
+	UpdateWarehouse(ctx context.Context, in *Warehouse, opts ...grpc.CallOption) (*WarehouseResponse, error)
+	GetWarehouse(ctx context.Context, in *WarehouseRequest, opts ...grpc.CallOption) (*WarehouseResponse, error)
+	ListWarehouses(ctx context.Context, in *WarehouseListRequest, opts ...grpc.CallOption) (*WarehouseListResponse, error)
+	DeleteWarehouse(ctx context.Context, in *WarehouseRequest, opts ...grpc.CallOption) (*Response, error)
+	ListMovements(ctx context.Context, in *MovementListRequest, opts ...grpc.CallOption) (*MovementListResponse, error)
+	ReconcileStock(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error)
+	WatchItems(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (InventoryService_WatchItemsClient, error)
+	ImportItems(ctx context.Context, opts ...grpc.CallOption) (InventoryService_ImportItemsClient, error)
+	ExportItems(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (InventoryService_ExportItemsClient, error)
+	GetThreshold(ctx context.Context, in *ThresholdRequest, opts ...grpc.CallOption) (*ThresholdResponse, error)
+	SetThreshold(ctx context.Context, in *ThresholdRequest, opts ...grpc.CallOption) (*ThresholdResponse, error)
 }
 
 type inventoryServiceClient struct {
@@ -68,6 +102,230 @@ func (c *inventoryServiceClient) RemoveItem(ctx context.Context, in *ItemRequest
 	return out, nil
 }
 
+func (c *inventoryServiceClient) Reserve(ctx context.Context, in *ReserveRequest, opts ...grpc.CallOption) (*ReserveResponse, error) {
+	out := new(ReserveResponse)
+	err := c.cc.Invoke(ctx, InventoryService_Reserve_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, InventoryService_CommitReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, InventoryService_ReleaseReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ExtendReservation(ctx context.Context, in *ExtendReservationRequest, opts ...grpc.CallOption) (*ReserveResponse, error) {
+	out := new(ReserveResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ExtendReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) Allocate(ctx context.Context, in *AllocateRequest, opts ...grpc.CallOption) (*AllocateResponse, error) {
+	out := new(AllocateResponse)
+	err := c.cc.Invoke(ctx, InventoryService_Allocate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CreateWarehouse(ctx context.Context, in *Warehouse, opts ...grpc.CallOption) (*WarehouseResponse, error) {
+	out := new(WarehouseResponse)
+	err := c.cc.Invoke(ctx, InventoryService_CreateWarehouse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) UpdateWarehouse(ctx context.Context, in *Warehouse, opts ...grpc.CallOption) (*WarehouseResponse, error) {
+	out := new(WarehouseResponse)
+	err := c.cc.Invoke(ctx, InventoryService_UpdateWarehouse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetWarehouse(ctx context.Context, in *WarehouseRequest, opts ...grpc.CallOption) (*WarehouseResponse, error) {
+	out := new(WarehouseResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetWarehouse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListWarehouses(ctx context.Context, in *WarehouseListRequest, opts ...grpc.CallOption) (*WarehouseListResponse, error) {
+	out := new(WarehouseListResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListWarehouses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) DeleteWarehouse(ctx context.Context, in *WarehouseRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, InventoryService_DeleteWarehouse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListMovements(ctx context.Context, in *MovementListRequest, opts ...grpc.CallOption) (*MovementListResponse, error) {
+	out := new(MovementListResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListMovements_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ReconcileStock(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error) {
+	out := new(ReconcileResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ReconcileStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) WatchItems(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (InventoryService_WatchItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[0], InventoryService_WatchItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inventoryServiceWatchItemsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InventoryService_WatchItemsClient interface {
+	Recv() (*ItemAvailableStatus, error)
+	grpc.ClientStream
+}
+
+type inventoryServiceWatchItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *inventoryServiceWatchItemsClient) Recv() (*ItemAvailableStatus, error) {
+	m := new(ItemAvailableStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *inventoryServiceClient) ImportItems(ctx context.Context, opts ...grpc.CallOption) (InventoryService_ImportItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[1], InventoryService_ImportItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inventoryServiceImportItemsClient{stream}
+	return x, nil
+}
+
+type InventoryService_ImportItemsClient interface {
+	Send(*ImportRow) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type inventoryServiceImportItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *inventoryServiceImportItemsClient) Send(m *ImportRow) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *inventoryServiceImportItemsClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *inventoryServiceClient) ExportItems(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (InventoryService_ExportItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[2], InventoryService_ExportItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &inventoryServiceExportItemsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InventoryService_ExportItemsClient interface {
+	Recv() (*Item, error)
+	grpc.ClientStream
+}
+
+type inventoryServiceExportItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *inventoryServiceExportItemsClient) Recv() (*Item, error) {
+	m := new(Item)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *inventoryServiceClient) GetThreshold(ctx context.Context, in *ThresholdRequest, opts ...grpc.CallOption) (*ThresholdResponse, error) {
+	out := new(ThresholdResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetThreshold_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) SetThreshold(ctx context.Context, in *ThresholdRequest, opts ...grpc.CallOption) (*ThresholdResponse, error) {
+	out := new(ThresholdResponse)
+	err := c.cc.Invoke(ctx, InventoryService_SetThreshold_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InventoryServiceServer is the server API for InventoryService service.
 // All implementations must embed UnimplementedInventoryServiceServer
 // for forward compatibility
@@ -75,6 +333,23 @@ type InventoryServiceServer interface {
 	CheckItem(context.Context, *ItemRequest) (*ItemAvailableResponse, error)
 	AddItem(context.Context, *ItemRequest) (*Response, error)
 	RemoveItem(context.Context, *ItemRequest) (*Response, error)
+	Reserve(context.Context, *ReserveRequest) (*ReserveResponse, error)
+	CommitReservation(context.Context, *ReservationRequest) (*Response, error)
+	ReleaseReservation(context.Context, *ReservationRequest) (*Response, error)
+	ExtendReservation(context.Context, *ExtendReservationRequest) (*ReserveResponse, error)
+	Allocate(context.Context, *AllocateRequest) (*AllocateResponse, error)
+	CreateWarehouse(context.Context, *Warehouse) (*WarehouseResponse, error)
+	UpdateWarehouse(context.Context, *Warehouse) (*WarehouseResponse, error)
+	GetWarehouse(context.Context, *WarehouseRequest) (*WarehouseResponse, error)
+	ListWarehouses(context.Context, *WarehouseListRequest) (*WarehouseListResponse, error)
+	DeleteWarehouse(context.Context, *WarehouseRequest) (*Response, error)
+	ListMovements(context.Context, *MovementListRequest) (*MovementListResponse, error)
+	ReconcileStock(context.Context, *ReconcileRequest) (*ReconcileResponse, error)
+	WatchItems(*WatchRequest, InventoryService_WatchItemsServer) error
+	ImportItems(InventoryService_ImportItemsServer) error
+	ExportItems(*ExportRequest, InventoryService_ExportItemsServer) error
+	GetThreshold(context.Context, *ThresholdRequest) (*ThresholdResponse, error)
+	SetThreshold(context.Context, *ThresholdRequest) (*ThresholdResponse, error)
 	mustEmbedUnimplementedInventoryServiceServer()
 }
 
@@ -91,6 +366,57 @@ func (UnimplementedInventoryServiceServer) AddItem(context.Context, *ItemRequest
 func (UnimplementedInventoryServiceServer) RemoveItem(context.Context, *ItemRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveItem not implemented")
 }
+func (UnimplementedInventoryServiceServer) Reserve(context.Context, *ReserveRequest) (*ReserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reserve not implemented")
+}
+func (UnimplementedInventoryServiceServer) CommitReservation(context.Context, *ReservationRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedInventoryServiceServer) ReleaseReservation(context.Context, *ReservationRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
+func (UnimplementedInventoryServiceServer) ExtendReservation(context.Context, *ExtendReservationRequest) (*ReserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendReservation not implemented")
+}
+func (UnimplementedInventoryServiceServer) Allocate(context.Context, *AllocateRequest) (*AllocateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Allocate not implemented")
+}
+func (UnimplementedInventoryServiceServer) CreateWarehouse(context.Context, *Warehouse) (*WarehouseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWarehouse not implemented")
+}
+func (UnimplementedInventoryServiceServer) UpdateWarehouse(context.Context, *Warehouse) (*WarehouseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWarehouse not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetWarehouse(context.Context, *WarehouseRequest) (*WarehouseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWarehouse not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListWarehouses(context.Context, *WarehouseListRequest) (*WarehouseListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWarehouses not implemented")
+}
+func (UnimplementedInventoryServiceServer) DeleteWarehouse(context.Context, *WarehouseRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWarehouse not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListMovements(context.Context, *MovementListRequest) (*MovementListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMovements not implemented")
+}
+func (UnimplementedInventoryServiceServer) ReconcileStock(context.Context, *ReconcileRequest) (*ReconcileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcileStock not implemented")
+}
+func (UnimplementedInventoryServiceServer) WatchItems(*WatchRequest, InventoryService_WatchItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) ImportItems(InventoryService_ImportItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) ExportItems(*ExportRequest, InventoryService_ExportItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetThreshold(context.Context, *ThresholdRequest) (*ThresholdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThreshold not implemented")
+}
+func (UnimplementedInventoryServiceServer) SetThreshold(context.Context, *ThresholdRequest) (*ThresholdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetThreshold not implemented")
+}
 func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
 
 // UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +484,326 @@ func _InventoryService_RemoveItem_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InventoryService_Reserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).Reserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_Reserve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).Reserve(ctx, req.(*ReserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CommitReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ReleaseReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ReleaseReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ExtendReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ExtendReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ExtendReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ExtendReservation(ctx, req.(*ExtendReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_Allocate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).Allocate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_Allocate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).Allocate(ctx, req.(*AllocateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CreateWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Warehouse)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateWarehouse(ctx, req.(*Warehouse))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_UpdateWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Warehouse)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).UpdateWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_UpdateWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).UpdateWarehouse(ctx, req.(*Warehouse))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarehouseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetWarehouse(ctx, req.(*WarehouseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListWarehouses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarehouseListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListWarehouses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListWarehouses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListWarehouses(ctx, req.(*WarehouseListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_DeleteWarehouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarehouseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).DeleteWarehouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_DeleteWarehouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).DeleteWarehouse(ctx, req.(*WarehouseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MovementListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListMovements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListMovements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListMovements(ctx, req.(*MovementListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ReconcileStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ReconcileStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ReconcileStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ReconcileStock(ctx, req.(*ReconcileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_WatchItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InventoryServiceServer).WatchItems(m, &inventoryServiceWatchItemsServer{stream})
+}
+
+type InventoryService_WatchItemsServer interface {
+	Send(*ItemAvailableStatus) error
+	grpc.ServerStream
+}
+
+type inventoryServiceWatchItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *inventoryServiceWatchItemsServer) Send(m *ItemAvailableStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InventoryService_ImportItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InventoryServiceServer).ImportItems(&inventoryServiceImportItemsServer{stream})
+}
+
+type InventoryService_ImportItemsServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportRow, error)
+	grpc.ServerStream
+}
+
+type inventoryServiceImportItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *inventoryServiceImportItemsServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *inventoryServiceImportItemsServer) Recv() (*ImportRow, error) {
+	m := new(ImportRow)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InventoryService_ExportItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InventoryServiceServer).ExportItems(m, &inventoryServiceExportItemsServer{stream})
+}
+
+type InventoryService_ExportItemsServer interface {
+	Send(*Item) error
+	grpc.ServerStream
+}
+
+type inventoryServiceExportItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *inventoryServiceExportItemsServer) Send(m *Item) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InventoryService_GetThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetThreshold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetThreshold(ctx, req.(*ThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_SetThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).SetThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_SetThreshold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).SetThreshold(ctx, req.(*ThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,7 +823,79 @@ var InventoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveItem",
 			Handler:    _InventoryService_RemoveItem_Handler,
 		},
+		{
+			MethodName: "Reserve",
+			Handler:    _InventoryService_Reserve_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _InventoryService_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _InventoryService_ReleaseReservation_Handler,
+		},
+		{
+			MethodName: "ExtendReservation",
+			Handler:    _InventoryService_ExtendReservation_Handler,
+		},
+		{
+			MethodName: "Allocate",
+			Handler:    _InventoryService_Allocate_Handler,
+		},
+		{
+			MethodName: "CreateWarehouse",
+			Handler:    _InventoryService_CreateWarehouse_Handler,
+		},
+		{
+			MethodName: "UpdateWarehouse",
+			Handler:    _InventoryService_UpdateWarehouse_Handler,
+		},
+		{
+			MethodName: "GetWarehouse",
+			Handler:    _InventoryService_GetWarehouse_Handler,
+		},
+		{
+			MethodName: "ListWarehouses",
+			Handler:    _InventoryService_ListWarehouses_Handler,
+		},
+		{
+			MethodName: "DeleteWarehouse",
+			Handler:    _InventoryService_DeleteWarehouse_Handler,
+		},
+		{
+			MethodName: "ListMovements",
+			Handler:    _InventoryService_ListMovements_Handler,
+		},
+		{
+			MethodName: "ReconcileStock",
+			Handler:    _InventoryService_ReconcileStock_Handler,
+		},
+		{
+			MethodName: "GetThreshold",
+			Handler:    _InventoryService_GetThreshold_Handler,
+		},
+		{
+			MethodName: "SetThreshold",
+			Handler:    _InventoryService_SetThreshold_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchItems",
+			Handler:       _InventoryService_WatchItems_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportItems",
+			Handler:       _InventoryService_ImportItems_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportItems",
+			Handler:       _InventoryService_ExportItems_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/inventory.proto",
 }